@@ -1,8 +1,11 @@
 package gorm
 
 import (
+	"database/sql"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
 	"gorm.io/gorm/clause"
@@ -18,6 +21,20 @@ type Association struct {
 	Error        error
 }
 
+// Pivot value of many2many association with the values of its join model record, see WithPivot
+type Pivot struct {
+	Value interface{}
+	Join  interface{}
+}
+
+// WithPivot appends value with the values of its join model record for many2many association,
+// the join model is setup with SetupJoinTable, existing join records are updated with the values
+//
+//	db.Model(&user).Association("Projects").Append(gorm.WithPivot(&project, ProjectMember{Role: "admin"}))
+func WithPivot(value interface{}, join interface{}) Pivot {
+	return Pivot{Value: value, Join: join}
+}
+
 func (db *DB) Association(column string) *Association {
 	association := &Association{DB: db}
 	table := db.Statement.Table
@@ -28,6 +45,8 @@ func (db *DB) Association(column string) *Association {
 
 		if association.Relationship == nil {
 			association.Error = fmt.Errorf("%w: %s", ErrUnsupportedRelation, column)
+		} else if association.Relationship.Type == schema.PolymorphicBelongsTo {
+			association.Error = fmt.Errorf("%w: %s is polymorphic belongs to relation", ErrUnsupportedRelation, column)
 		}
 
 		db.Statement.ReflectValue = reflect.ValueOf(db.Statement.Model)
@@ -58,7 +77,7 @@ func (association *Association) Find(out interface{}, conds ...interface{}) erro
 }
 
 func (association *Association) Append(values ...interface{}) error {
-	if association.Error == nil {
+	if association.checkReadOnly(); association.Error == nil {
 		switch association.Relationship.Type {
 		case schema.HasOne, schema.BelongsTo:
 			if len(values) > 0 {
@@ -73,7 +92,7 @@ func (association *Association) Append(values ...interface{}) error {
 }
 
 func (association *Association) Replace(values ...interface{}) error {
-	if association.Error == nil {
+	if association.checkReadOnly(); association.Error == nil {
 		reflectValue := association.DB.Statement.ReflectValue
 		rel := association.Relationship
 
@@ -183,6 +202,7 @@ func (association *Association) Replace(values ...interface{}) error {
 				return ErrPrimaryKeyRequired
 			}
 
+			values, _ = unwrapPivots(values)
 			_, rvs := schema.GetIdentityFieldValuesMapFromValues(association.DB.Statement.Context, values, relPrimaryFields)
 			if relColumn, relValues := schema.ToQueryValues(rel.JoinTable.Table, joinRelPrimaryKeys, rvs); len(relValues) > 0 {
 				tx.Where(clause.Not(clause.IN{Column: relColumn, Values: relValues}))
@@ -195,7 +215,7 @@ func (association *Association) Replace(values ...interface{}) error {
 }
 
 func (association *Association) Delete(values ...interface{}) error {
-	if association.Error == nil {
+	if association.checkReadOnly(); association.Error == nil {
 		var (
 			reflectValue  = association.DB.Statement.ReflectValue
 			rel           = association.Relationship
@@ -364,6 +384,388 @@ func (association *Association) Clear() error {
 	return association.Replace()
 }
 
+// Move moves value to index of the positioned associations, and renumbers their positions in order
+func (association *Association) Move(value interface{}, index int) error {
+	if association.checkReadOnly(); association.Error != nil {
+		return association.Error
+	}
+
+	var (
+		ctx           = association.DB.Statement.Context
+		reflectValue  = association.DB.Statement.ReflectValue
+		rel           = association.Relationship
+		positionField = rel.PositionField()
+		current       = rel.FieldSchema.MakeSlice()
+		records       []reflect.Value
+		moved         = -1
+	)
+
+	if positionField == nil {
+		association.Error = fmt.Errorf("%w: %s should be positioned has many or many2many relation", ErrUnsupportedRelation, rel.Name)
+		return association.Error
+	} else if reflectValue.Kind() != reflect.Struct {
+		association.Error = fmt.Errorf("%w: move supports single record only, got %v", ErrInvalidValue, reflectValue.Type())
+		return association.Error
+	}
+
+	keyOf := func(rv reflect.Value) string {
+		values := make([]interface{}, len(rel.FieldSchema.PrimaryFields))
+		for idx, field := range rel.FieldSchema.PrimaryFields {
+			values[idx], _ = field.ValueOf(ctx, rv)
+		}
+		return utils.ToStringKey(values...)
+	}
+
+	orderBy := clause.OrderBy{Columns: []clause.OrderByColumn{{Column: clause.Column{Table: positionField.Schema.Table, Name: positionField.DBName}}}}
+	for _, field := range rel.FieldSchema.PrimaryFields {
+		orderBy.Columns = append(orderBy.Columns, clause.OrderByColumn{Column: clause.Column{Table: rel.FieldSchema.Table, Name: field.DBName}})
+	}
+
+	currentAssociation := &Association{DB: association.DB.Session(&Session{}), Relationship: rel, Unscope: association.Unscope}
+	if association.Error = currentAssociation.buildCondition().Clauses(orderBy).Find(current.Interface()).Error; association.Error != nil {
+		return association.Error
+	}
+
+	key := keyOf(reflect.Indirect(reflect.ValueOf(value)))
+	for i := 0; i < current.Elem().Len(); i++ {
+		if record := current.Elem().Index(i); keyOf(record) == key {
+			moved = i
+		} else {
+			records = append(records, record)
+		}
+	}
+
+	if moved < 0 {
+		association.Error = ErrRecordNotFound
+		return association.Error
+	}
+
+	if index < 0 {
+		index = 0
+	} else if index > len(records) {
+		index = len(records)
+	}
+	records = append(records[:index], append([]reflect.Value{current.Elem().Index(moved)}, records[index:]...)...)
+
+	var (
+		positionModel = reflect.New(positionField.Schema.ModelType).Interface()
+		joinConds     []clause.Expression
+		joinPositions map[string]interface{}
+	)
+
+	// key of join records by the associated records, e.g: language_code of user_languages
+	joinKeyOf := func(rv reflect.Value, isJoinRecord bool) string {
+		values := make([]interface{}, 0, len(rel.References))
+		for _, ref := range rel.References {
+			if !ref.OwnPrimaryKey && ref.PrimaryValue == "" {
+				if isJoinRecord {
+					v, _ := ref.ForeignKey.ValueOf(ctx, rv)
+					values = append(values, v)
+				} else {
+					v, _ := ref.PrimaryKey.ValueOf(ctx, rv)
+					values = append(values, v)
+				}
+			}
+		}
+		return utils.ToStringKey(values...)
+	}
+
+	newPositionTx := func(tx *DB) *DB {
+		tx = tx.Model(positionModel).Table(positionField.Schema.Table)
+		if association.Unscope && positionField.Schema != rel.JoinTable {
+			tx = tx.Unscoped()
+		}
+		return tx
+	}
+
+	if positionField.Schema == rel.JoinTable {
+		for _, ref := range rel.References {
+			if ref.OwnPrimaryKey {
+				v, _ := ref.PrimaryKey.ValueOf(ctx, reflectValue)
+				joinConds = append(joinConds, clause.Eq{Column: clause.Column{Table: rel.JoinTable.Table, Name: ref.ForeignKey.DBName}, Value: v})
+			} else if ref.PrimaryValue != "" {
+				joinConds = append(joinConds, clause.Eq{Column: clause.Column{Table: rel.JoinTable.Table, Name: ref.ForeignKey.DBName}, Value: ref.PrimaryValue})
+			}
+		}
+
+		// positions of join records not soft deleted
+		joinRecords := rel.JoinTable.MakeSlice()
+		if association.Error = newPositionTx(association.DB.Session(&Session{NewDB: true})).Where(clause.And(joinConds...)).Find(joinRecords.Interface()).Error; association.Error != nil {
+			return association.Error
+		}
+
+		joinPositions = make(map[string]interface{}, joinRecords.Elem().Len())
+		for i := 0; i < joinRecords.Elem().Len(); i++ {
+			joinRecord := joinRecords.Elem().Index(i)
+			joinPositions[joinKeyOf(joinRecord, true)], _ = positionField.ValueOf(ctx, joinRecord)
+		}
+	}
+
+	association.Error = association.DB.Session(&Session{NewDB: true}).Transaction(func(tx *DB) error {
+		for position, record := range records {
+			var (
+				conds           []clause.Expression
+				currentPosition interface{}
+			)
+
+			if positionField.Schema == rel.JoinTable {
+				currentPosition = joinPositions[joinKeyOf(record, false)]
+				conds = append(conds, joinConds...)
+				for _, ref := range rel.References {
+					if !ref.OwnPrimaryKey && ref.PrimaryValue == "" {
+						v, _ := ref.PrimaryKey.ValueOf(ctx, record)
+						conds = append(conds, clause.Eq{Column: clause.Column{Table: rel.JoinTable.Table, Name: ref.ForeignKey.DBName}, Value: v})
+					}
+				}
+			} else {
+				currentPosition, _ = positionField.ValueOf(ctx, record)
+				for _, field := range rel.FieldSchema.PrimaryFields {
+					v, _ := field.ValueOf(ctx, record)
+					conds = append(conds, clause.Eq{Column: clause.Column{Table: rel.FieldSchema.Table, Name: field.DBName}, Value: v})
+				}
+			}
+
+			// only update the records whose position changed
+			if currentPosition != nil && utils.ToString(currentPosition) == strconv.Itoa(position) {
+				continue
+			}
+
+			if err := newPositionTx(tx).Where(clause.And(conds...)).UpdateColumn(positionField.DBName, position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	// reorder loaded associations
+	if fieldValue := reflect.Indirect(rel.Field.ReflectValueOf(ctx, reflectValue)); association.Error == nil && fieldValue.Len() > 0 {
+		positions := make(map[string]int, len(records))
+		for position, record := range records {
+			positions[keyOf(record)] = position
+		}
+
+		positionOf := func(i int) int {
+			if position, ok := positions[keyOf(fieldValue.Index(i))]; ok {
+				return position
+			}
+			return len(records)
+		}
+
+		sort.SliceStable(fieldValue.Interface(), func(i, j int) bool {
+			return positionOf(i) < positionOf(j)
+		})
+
+		if positionField.Schema == rel.FieldSchema {
+			for i := 0; i < fieldValue.Len(); i++ {
+				if position, ok := positions[keyOf(fieldValue.Index(i))]; ok {
+					association.Error = positionField.Set(ctx, fieldValue.Index(i), position)
+				}
+			}
+		}
+	}
+
+	return association.Error
+}
+
+// SyncResult primary keys of the associations attached and detached by Sync, composite primary keys are returned as []interface{}
+type SyncResult struct {
+	Attached []interface{}
+	Detached []interface{}
+}
+
+// Sync replaces associations with values like Replace, but only attaches the values not associated yet
+// and detaches the associations not in values in one transaction, returns their primary keys
+func (association *Association) Sync(values ...interface{}) (result SyncResult, err error) {
+	if association.checkReadOnly(); association.Error != nil {
+		return result, association.Error
+	}
+
+	var (
+		ctx          = association.DB.Statement.Context
+		reflectValue = association.DB.Statement.ReflectValue
+		rel          = association.Relationship
+		current      = rel.FieldSchema.MakeSlice()
+		records      []reflect.Value
+		recordJoins  []interface{}
+		recordKeys   = map[string]bool{}
+		keptKeys     = map[string]bool{}
+		attaches     []interface{}
+		attached     []reflect.Value
+		detaches     []interface{}
+		joins        []interface{}
+	)
+
+	if reflectValue.Kind() != reflect.Struct {
+		association.Error = fmt.Errorf("%w: sync supports single record only, got %v", ErrInvalidValue, reflectValue.Type())
+		return result, association.Error
+	}
+
+	primaryKeyOf := func(rv reflect.Value) (string, interface{}, bool) {
+		values := make([]interface{}, 0, len(rel.FieldSchema.PrimaryFields))
+		notZero := false
+		for _, field := range rel.FieldSchema.PrimaryFields {
+			v, isZero := field.ValueOf(ctx, rv)
+			values = append(values, v)
+			notZero = notZero || !isZero
+		}
+
+		if len(values) == 1 {
+			return utils.ToStringKey(values...), values[0], notZero
+		}
+		return utils.ToStringKey(values...), values, notZero
+	}
+
+	appendRecord := func(rv reflect.Value, join interface{}) {
+		if rv.Kind() == reflect.Ptr {
+			rv = rv.Elem()
+		}
+
+		if rv.Type() != rel.FieldSchema.ModelType {
+			association.Error = fmt.Errorf("unsupported data type: %v for relation %s", rv.Type(), rel.Name)
+			return
+		}
+
+		// values with the same primary key are synced once
+		if key, _, notZero := primaryKeyOf(rv); notZero {
+			if recordKeys[key] {
+				return
+			}
+			recordKeys[key] = true
+		}
+
+		recordJoins = append(recordJoins, join)
+		if rv.CanAddr() {
+			records = append(records, rv.Addr())
+		} else {
+			record := reflect.New(rv.Type())
+			record.Elem().Set(rv)
+			records = append(records, record)
+		}
+	}
+
+	values, joins = unwrapPivots(values)
+	for idx, value := range values {
+		switch rv := reflect.Indirect(reflect.ValueOf(value)); rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				appendRecord(rv.Index(i), joins[idx])
+			}
+		default:
+			appendRecord(rv, joins[idx])
+		}
+	}
+
+	if association.Error != nil {
+		return result, association.Error
+	}
+
+	// operate with new sessions, avoid changing the statement of association.DB
+	newAssociation := func() *Association {
+		return &Association{DB: association.DB.Session(&Session{}), Relationship: rel, Unscope: association.Unscope}
+	}
+
+	if association.Error = newAssociation().buildCondition().Find(current.Interface()).Error; association.Error != nil {
+		return result, association.Error
+	}
+
+	currentKeys := map[string]bool{}
+	for i := 0; i < current.Elem().Len(); i++ {
+		key, _, _ := primaryKeyOf(current.Elem().Index(i))
+		currentKeys[key] = true
+	}
+
+	for idx, record := range records {
+		key, _, notZero := primaryKeyOf(record)
+		kept := notZero && currentKeys[key]
+		if kept {
+			keptKeys[key] = true
+		} else {
+			attached = append(attached, record)
+		}
+
+		if join := recordJoins[idx]; join != nil {
+			// join model values of kept values are updated also
+			attaches = append(attaches, WithPivot(record.Interface(), join))
+		} else if !kept {
+			attaches = append(attaches, record.Interface())
+		}
+	}
+
+	for i := 0; i < current.Elem().Len(); i++ {
+		if key, primaryKey, _ := primaryKeyOf(current.Elem().Index(i)); !keptKeys[key] {
+			detaches = append(detaches, current.Elem().Index(i).Interface())
+			result.Detached = append(result.Detached, primaryKey)
+		}
+	}
+
+	// detach and attach in one transaction, so the associations are not left half-synced if any of them failed
+	originalValue, _ := rel.Field.ValueOf(ctx, reflectValue)
+	association.Error = association.DB.Transaction(func(tx *DB) (err error) {
+		defer func() {
+			// restore the associations in memory, which might be changed by detaching or attaching
+			if err != nil {
+				if setErr := rel.Field.Set(ctx, reflectValue, originalValue); setErr != nil {
+					err = fmt.Errorf("%w, failed to restore associations: %v", err, setErr)
+				}
+			}
+		}()
+
+		txAssociation := func() *Association {
+			return &Association{DB: tx.Session(&Session{}), Relationship: rel, Unscope: association.Unscope}
+		}
+
+		if len(detaches) > 0 {
+			if err = txAssociation().Delete(detaches...); err != nil {
+				return err
+			}
+		}
+
+		if len(attaches) > 0 {
+			// only save the attached values
+			if rel.Type == schema.HasMany || rel.Type == schema.Many2Many {
+				if err = rel.Field.Set(ctx, reflectValue, reflect.New(rel.Field.IndirectFieldType).Interface()); err != nil {
+					return err
+				}
+			}
+
+			if err = txAssociation().Append(attaches...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if association.Error != nil {
+		return SyncResult{}, association.Error
+	}
+
+	for _, record := range attached {
+		_, primaryKey, _ := primaryKeyOf(record)
+		result.Attached = append(result.Attached, primaryKey)
+	}
+
+	switch rel.Type {
+	case schema.HasMany, schema.Many2Many:
+		fieldValue := reflect.MakeSlice(rel.Field.IndirectFieldType, 0, len(records))
+		for _, record := range records {
+			if record.Type().AssignableTo(fieldValue.Type().Elem()) {
+				fieldValue = reflect.Append(fieldValue, record)
+			} else {
+				fieldValue = reflect.Append(fieldValue, record.Elem())
+			}
+		}
+		association.Error = rel.Field.Set(ctx, reflectValue, fieldValue.Interface())
+	default:
+		if len(records) == 0 {
+			association.Error = rel.Field.Set(ctx, reflectValue, reflect.New(rel.Field.IndirectFieldType).Interface())
+		} else if len(attaches) == 0 {
+			association.Error = rel.Field.Set(ctx, reflectValue, records[0].Interface())
+		}
+	}
+
+	return result, association.Error
+}
+
 func (association *Association) Count() (count int64) {
 	if association.Error == nil {
 		association.Error = association.buildCondition().Count(&count).Error
@@ -371,6 +773,63 @@ func (association *Association) Count() (count int64) {
 	return
 }
 
+// checkReadOnly reports error for has many through relationship, which can't be modified with association mode
+func (association *Association) checkReadOnly() {
+	if association.Error == nil && association.Relationship.Type == schema.HasManyThrough {
+		association.Error = fmt.Errorf("%w: %s is read-only", ErrUnsupportedRelation, association.Relationship.Name)
+	}
+}
+
+// nextPosition returns the next position of the positioned associations of source
+func (association *Association) nextPosition(source reflect.Value) (int, error) {
+	var (
+		rel           = association.Relationship
+		positionField = rel.PositionField()
+		table         = positionField.Schema.Table
+		conds         = make([]clause.Expression, 0, len(rel.References))
+		position      sql.NullInt64
+	)
+
+	for _, ref := range rel.References {
+		if ref.OwnPrimaryKey {
+			value, _ := ref.PrimaryKey.ValueOf(association.DB.Statement.Context, source)
+			conds = append(conds, clause.Eq{Column: clause.Column{Table: table, Name: ref.ForeignKey.DBName}, Value: value})
+		} else if ref.PrimaryValue != "" {
+			conds = append(conds, clause.Eq{Column: clause.Column{Table: table, Name: ref.ForeignKey.DBName}, Value: ref.PrimaryValue})
+		}
+	}
+
+	err := association.DB.Session(&Session{NewDB: true}).Table(table).Select("MAX(?)", clause.Column{Table: table, Name: positionField.DBName}).
+		Where(clause.And(conds...)).Scan(&position).Error
+	if position.Valid {
+		return int(position.Int64) + 1, err
+	}
+	return 0, err
+}
+
+// unwrapPivots returns the values of pivots and their join model values
+func unwrapPivots(values []interface{}) ([]interface{}, []interface{}) {
+	var (
+		results = make([]interface{}, len(values))
+		joins   = make([]interface{}, len(values))
+	)
+
+	for idx, value := range values {
+		if pivot, ok := value.(Pivot); ok {
+			results[idx], joins[idx] = pivot.Value, pivot.Join
+		} else {
+			results[idx] = value
+		}
+	}
+	return results, joins
+}
+
+// pivotKeyOf returns the key of join model value of associated value, which is made of the address of source and the
+// index of value in its association field, as new values only get primary keys when saved with source
+func pivotKeyOf(source reflect.Value, index int) string {
+	return utils.ToStringKey(reflect.Indirect(source).Addr().Pointer(), index)
+}
+
 type assignBack struct {
 	Source reflect.Value
 	Index  int
@@ -380,10 +839,33 @@ type assignBack struct {
 func (association *Association) saveAssociation(clear bool, values ...interface{}) {
 	var (
 		reflectValue = association.DB.Statement.ReflectValue
-		assignBacks  []assignBack // assign association values back to arguments after save
+		assignBacks  []assignBack               // assign association values back to arguments after save
+		pivots       = map[string]interface{}{} // join model values of appended values, keyed by source and index of value
+		joins        []interface{}
+		positions    = map[uintptr]int{} // next positions of sources for positioned relation
+		replace      = clear
 	)
 
-	appendToRelations := func(source, rv reflect.Value, clear bool) {
+	nextPosition := func(source reflect.Value) int {
+		key := source.Addr().Pointer()
+		position, ok := positions[key]
+		if !ok && !replace {
+			position, association.Error = association.nextPosition(source)
+		}
+		positions[key] = position + 1
+		return position
+	}
+
+	if values, joins = unwrapPivots(values); association.Relationship.Type != schema.Many2Many {
+		for _, join := range joins {
+			if join != nil {
+				association.Error = fmt.Errorf("%w: pivot of %s, should be many2many relation", ErrUnsupportedRelation, association.Relationship.Name)
+				return
+			}
+		}
+	}
+
+	appendToRelations := func(source, rv reflect.Value, clear bool, join interface{}) {
 		switch association.Relationship.Type {
 		case schema.HasOne, schema.BelongsTo:
 			switch rv.Kind() {
@@ -427,6 +909,7 @@ func (association *Association) saveAssociation(clear bool, values ...interface{
 				}
 			}
 
+			appendedLen := fieldValue.Len()
 			switch rv.Kind() {
 			case reflect.Slice, reflect.Array:
 				for i := 0; i < rv.Len(); i++ {
@@ -439,6 +922,34 @@ func (association *Association) saveAssociation(clear bool, values ...interface{
 			if association.Error == nil {
 				association.Error = association.Relationship.Field.Set(association.DB.Statement.Context, source, fieldValue.Interface())
 			}
+
+			positionField := association.Relationship.PositionField()
+			for i := appendedLen; i < fieldValue.Len() && (join != nil || positionField != nil); i++ {
+				elem, pivot := fieldValue.Index(i), join
+				if elem.Kind() != reflect.Ptr {
+					elem = elem.Addr()
+				}
+
+				if positionField != nil {
+					// assign the next position to appended values, or the join model record of many2many relation
+					if joinSchema := association.Relationship.JoinTable; positionField.Schema == joinSchema {
+						joinValue := reflect.New(joinSchema.ModelType)
+						if jv := reflect.Indirect(reflect.ValueOf(join)); join == nil || jv.Type() == joinSchema.ModelType {
+							if join != nil {
+								joinValue.Elem().Set(jv)
+							}
+							association.Error = positionField.Set(association.DB.Statement.Context, joinValue, nextPosition(source))
+							pivot = joinValue.Interface()
+						}
+					} else {
+						association.Error = positionField.Set(association.DB.Statement.Context, elem, nextPosition(source))
+					}
+				}
+
+				if pivot != nil {
+					pivots[pivotKeyOf(source, i)] = pivot
+				}
+			}
 		}
 	}
 
@@ -477,7 +988,7 @@ func (association *Association) saveAssociation(clear bool, values ...interface{
 	if len(omitColumns) > 0 {
 		associationDB.Omit(omitColumns...)
 	}
-	associationDB = associationDB.Session(&Session{})
+	associationDB = associationDB.Set("gorm:pivots", pivots).Session(&Session{})
 
 	switch reflectValue.Kind() {
 	case reflect.Slice, reflect.Array:
@@ -509,7 +1020,9 @@ func (association *Association) saveAssociation(clear bool, values ...interface{
 		}
 
 		for i := 0; i < reflectValue.Len(); i++ {
-			appendToRelations(reflectValue.Index(i), reflect.Indirect(reflect.ValueOf(values[i])), clear)
+			if appendToRelations(reflectValue.Index(i), reflect.Indirect(reflect.ValueOf(values[i])), clear, joins[i]); association.Error != nil {
+				return
+			}
 
 			// TODO support save slice data, sql with case?
 			association.Error = associationDB.Updates(reflectValue.Index(i).Addr().Interface()).Error
@@ -530,7 +1043,9 @@ func (association *Association) saveAssociation(clear bool, values ...interface{
 
 		for idx, value := range values {
 			rv := reflect.Indirect(reflect.ValueOf(value))
-			appendToRelations(reflectValue, rv, clear && idx == 0)
+			if appendToRelations(reflectValue, rv, clear && idx == 0, joins[idx]); association.Error != nil {
+				return
+			}
 		}
 
 		if len(values) > 0 {
@@ -555,7 +1070,39 @@ func (association *Association) buildCondition() *DB {
 		tx         = association.DB.Model(modelValue)
 	)
 
-	if association.Relationship.JoinTable != nil {
+	if through := association.Relationship.Through; len(through) > 0 {
+		var (
+			throughTable = through[0].FieldSchema.Table
+			joinConds    = make([]clause.Expression, 0, len(through[1].References))
+		)
+
+		for _, ref := range through[1].References {
+			if ref.OwnPrimaryKey {
+				joinConds = append(joinConds, clause.Eq{
+					Column: clause.Column{Table: through[1].FieldSchema.Table, Name: ref.ForeignKey.DBName},
+					Value:  clause.Column{Table: throughTable, Name: ref.PrimaryKey.DBName},
+				})
+			} else if ref.PrimaryValue != "" {
+				joinConds = append(joinConds, clause.Eq{Column: clause.Column{Table: through[1].FieldSchema.Table, Name: ref.ForeignKey.DBName}, Value: ref.PrimaryValue})
+			}
+		}
+
+		if !tx.Statement.Unscoped && len(through[0].FieldSchema.QueryClauses) > 0 {
+			throughStmt := Statement{DB: tx, Context: tx.Statement.Context, Schema: through[0].FieldSchema, Table: throughTable, Clauses: map[string]clause.Clause{}}
+			for _, queryClause := range through[0].FieldSchema.QueryClauses {
+				throughStmt.AddClause(queryClause)
+			}
+			throughStmt.Build("WHERE")
+			if len(throughStmt.SQL.String()) > 0 {
+				tx.Clauses(clause.Expr{SQL: strings.Replace(throughStmt.SQL.String(), "WHERE ", "", 1), Vars: throughStmt.Vars})
+			}
+		}
+
+		tx = tx.Session(&Session{QueryFields: true}).Clauses(clause.From{Joins: []clause.Join{{
+			Table: clause.Table{Name: throughTable},
+			ON:    clause.Where{Exprs: joinConds},
+		}}}).Where(clause.Where{Exprs: through[0].ToQueryConditions(association.DB.Statement.Context, association.DB.Statement.ReflectValue)})
+	} else if association.Relationship.JoinTable != nil {
 		if !tx.Statement.Unscoped && len(association.Relationship.JoinTable.QueryClauses) > 0 {
 			joinStmt := Statement{DB: tx, Context: tx.Statement.Context, Schema: association.Relationship.JoinTable, Table: association.Relationship.JoinTable.Table, Clauses: map[string]clause.Clause{}}
 			for _, queryClause := range association.Relationship.JoinTable.QueryClauses {
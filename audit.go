@@ -0,0 +1,463 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
+)
+
+// audit actions
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+const auditLogsKey = "gorm:audit_logs"
+
+type actorCtxKey struct{}
+
+// WithActor returns a copy of ctx that carries the actor who performs the changes
+//
+//	db.WithContext(gorm.WithActor(ctx, "jinzhu")).Delete(&user)
+func WithActor(ctx context.Context, actor interface{}) context.Context {
+	return context.WithValue(ctx, actorCtxKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored in ctx by WithActor
+func ActorFromContext(ctx context.Context) (interface{}, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	actor := ctx.Value(actorCtxKey{})
+	return actor, actor != nil
+}
+
+// Auditable models implementing Auditable and returning true are recorded by AuditPlugin
+type Auditable interface {
+	Auditable() bool
+}
+
+// AuditLog audit trail record written by AuditPlugin
+type AuditLog struct {
+	ID         uint                   `gorm:"primarykey"`
+	Table      string                 `gorm:"size:255;index"`
+	PrimaryKey string                 `gorm:"size:255;index"`
+	Action     string                 `gorm:"size:16"`
+	Actor      string                 `gorm:"size:255"`
+	OldValues  map[string]interface{} `gorm:"serializer:json"`
+	NewValues  map[string]interface{} `gorm:"serializer:json"`
+	CreatedAt  time.Time
+}
+
+// AuditPlugin records field level changes of Auditable models into an audit table,
+// within the same transaction as the changes
+//
+//	db.Use(&gorm.AuditPlugin{Table: "audit_logs"})
+type AuditPlugin struct {
+	// Table audit records table, default is `audit_logs`
+	Table string
+	// Actor returns the actor performing changes, default uses the actor stored by WithActor
+	Actor func(ctx context.Context) string
+}
+
+// Name implements Plugin interface
+func (p *AuditPlugin) Name() string {
+	return "gorm:audit"
+}
+
+// Initialize implements Plugin interface
+func (p *AuditPlugin) Initialize(db *DB) error {
+	if p.Table == "" {
+		p.Table = "audit_logs"
+	}
+
+	if p.Actor == nil {
+		p.Actor = func(ctx context.Context) string {
+			if actor, ok := ActorFromContext(ctx); ok {
+				return fmt.Sprint(actor)
+			}
+			return ""
+		}
+	}
+
+	createCallback := db.Callback().Create()
+	if err := createCallback.After("gorm:create").Before("gorm:commit_or_rollback_transaction").Register("gorm:audit_after_create", p.afterCreate); err != nil {
+		return err
+	}
+
+	updateCallback := db.Callback().Update()
+	if err := updateCallback.After("gorm:before_update").Before("gorm:update").Register("gorm:audit_before_update", p.beforeUpdate); err != nil {
+		return err
+	}
+	if err := updateCallback.After("gorm:update").Before("gorm:commit_or_rollback_transaction").Register("gorm:audit_after_update", p.afterChange); err != nil {
+		return err
+	}
+
+	deleteCallback := db.Callback().Delete()
+	if err := deleteCallback.After("gorm:before_delete").Before("gorm:delete").Register("gorm:audit_before_delete", p.beforeDelete); err != nil {
+		return err
+	}
+	return deleteCallback.After("gorm:delete").Before("gorm:commit_or_rollback_transaction").Register("gorm:audit_after_delete", p.afterChange)
+}
+
+func (p *AuditPlugin) auditable(db *DB) bool {
+	if db.Error != nil || db.DryRun || db.Statement.Schema == nil {
+		return false
+	}
+
+	if auditable, ok := reflect.New(db.Statement.Schema.ModelType).Interface().(Auditable); ok {
+		return auditable.Auditable()
+	}
+	return false
+}
+
+func (p *AuditPlugin) afterCreate(db *DB) {
+	if !p.auditable(db) {
+		return
+	}
+
+	// records skipped by conflicts, e.g: ON CONFLICT DO NOTHING
+	if db.RowsAffected == 0 {
+		return
+	}
+
+	var (
+		stmt = db.Statement
+		logs []AuditLog
+	)
+
+	mapToLog := func(values map[string]interface{}) AuditLog {
+		newValues := make(map[string]interface{}, len(values))
+		pk := values["@id"]
+		for key, value := range values {
+			if field := stmt.Schema.LookUpField(key); field != nil && field.DBName != "" {
+				newValues[field.DBName] = value
+				if field == stmt.Schema.PrioritizedPrimaryField {
+					pk = value
+				}
+			}
+		}
+		return p.newLog(stmt, AuditActionCreate, utils.ToStringKey(pk), nil, newValues)
+	}
+
+	switch values := stmt.Dest.(type) {
+	case map[string]interface{}:
+		logs = append(logs, mapToLog(values))
+	case *map[string]interface{}:
+		logs = append(logs, mapToLog(*values))
+	case []map[string]interface{}:
+		for _, value := range values {
+			logs = append(logs, mapToLog(value))
+		}
+	case *[]map[string]interface{}:
+		for _, value := range *values {
+			logs = append(logs, mapToLog(value))
+		}
+	default:
+		p.eachRecord(stmt, stmt.ReflectValue, func(idx int, rv reflect.Value) {
+			// primary keys of records skipped by conflicts are not assigned
+			if primaryKey, ok := p.primaryKey(stmt, rv); ok || len(stmt.Schema.PrimaryFields) == 0 {
+				logs = append(logs, p.newLog(stmt, AuditActionCreate, primaryKey, nil, p.valuesOf(stmt, rv)))
+			}
+		})
+	}
+
+	p.write(db, logs)
+}
+
+// beforeUpdate collects the changes of current statement before updating, old values are the values of the updating
+// records, or their snapshots when updating with themselves, new values are the assigned values, changed values are
+// decided by Statement.Changed, updates without the primary keys of the records load the affected rows with the
+// same conditions and record one log for each of them
+func (p *AuditPlugin) beforeUpdate(db *DB) {
+	if !p.auditable(db) || db.Statement.SQL.Len() > 0 {
+		return
+	}
+
+	var (
+		stmt         = db.Statement
+		assigned     = p.assignedValues(stmt)
+		curDestIndex = stmt.CurDestIndex
+		logs         []AuditLog
+		identified   bool
+	)
+	defer func() {
+		stmt.CurDestIndex = curDestIndex
+	}()
+
+	if len(assigned) == 0 {
+		return
+	}
+
+	p.eachRecord(stmt, stmt.ReflectValue, func(idx int, rv reflect.Value) {
+		primaryKey, ok := p.primaryKey(stmt, rv)
+		if !ok {
+			return
+		}
+		identified = true
+		stmt.CurDestIndex = idx
+
+		oldValues, newValues := map[string]interface{}{}, map[string]interface{}{}
+		if dest := reflect.ValueOf(stmt.Dest); dest.Kind() == reflect.Ptr && rv.CanAddr() && dest.Pointer() == rv.Addr().Pointer() {
+			// updating with the record itself, old values are only known from its snapshot
+			changes := stmt.Changes()
+			for dbName, value := range assigned {
+				if changes == nil {
+					newValues[dbName] = value
+				} else if change, ok := changes[dbName]; ok {
+					oldValues[dbName], newValues[dbName] = change.Old, change.New
+				}
+			}
+
+			if changes == nil {
+				oldValues = nil
+			}
+		} else {
+			for dbName, value := range assigned {
+				if field := stmt.Schema.FieldsByDBName[dbName]; stmt.Changed(field.Name) {
+					oldValues[dbName] = field.ReflectValueOf(stmt.Context, rv).Interface()
+					newValues[dbName] = value
+				}
+			}
+		}
+
+		if len(newValues) > 0 {
+			logs = append(logs, p.newLog(stmt, AuditActionUpdate, primaryKey, oldValues, newValues))
+		}
+	})
+
+	if !identified {
+		records, ok := p.affectedRecords(db)
+		if !ok {
+			return
+		}
+
+		p.eachRecord(stmt, records, func(idx int, rv reflect.Value) {
+			primaryKey, _ := p.primaryKey(stmt, rv)
+			oldValues, newValues := map[string]interface{}{}, map[string]interface{}{}
+			for dbName, value := range assigned {
+				field := stmt.Schema.FieldsByDBName[dbName]
+				if p.changed(stmt, field, rv, value) {
+					oldValues[dbName] = field.ReflectValueOf(stmt.Context, rv).Interface()
+					newValues[dbName] = value
+				}
+			}
+
+			if len(newValues) > 0 {
+				logs = append(logs, p.newLog(stmt, AuditActionUpdate, primaryKey, oldValues, newValues))
+			}
+		})
+	}
+
+	db.InstanceSet(auditLogsKey, logs)
+}
+
+// beforeDelete collects the values of deleting records, deletes without the primary keys of the records load the
+// affected rows with the same conditions and record one log for each of them
+func (p *AuditPlugin) beforeDelete(db *DB) {
+	if !p.auditable(db) || db.Statement.SQL.Len() > 0 {
+		return
+	}
+
+	var (
+		stmt = db.Statement
+		logs []AuditLog
+	)
+
+	p.eachRecord(stmt, stmt.ReflectValue, func(idx int, rv reflect.Value) {
+		if primaryKey, ok := p.primaryKey(stmt, rv); ok {
+			logs = append(logs, p.newLog(stmt, AuditActionDelete, primaryKey, p.valuesOf(stmt, rv), nil))
+		}
+	})
+
+	if len(logs) == 0 {
+		records, ok := p.affectedRecords(db)
+		if !ok {
+			return
+		}
+
+		p.eachRecord(stmt, records, func(idx int, rv reflect.Value) {
+			primaryKey, _ := p.primaryKey(stmt, rv)
+			logs = append(logs, p.newLog(stmt, AuditActionDelete, primaryKey, p.valuesOf(stmt, rv), nil))
+		})
+	}
+
+	db.InstanceSet(auditLogsKey, logs)
+}
+
+// affectedRecords loads the records matching current statement within its transaction, with the same table
+// expression, joins, FROM and WHERE clauses, returns false if the statement has no conditions and global updates are
+// not allowed
+func (p *AuditPlugin) affectedRecords(db *DB) (reflect.Value, bool) {
+	var (
+		stmt    = db.Statement
+		records = stmt.Schema.MakeSlice()
+		tx      = p.session(db).Table(stmt.Table)
+		where   clause.Where
+	)
+
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		where, _ = c.Expression.(clause.Where)
+	}
+
+	if len(where.Exprs) == 0 && !db.AllowGlobalUpdate {
+		return reflect.Value{}, false
+	}
+
+	tx.Statement.TableExpr = stmt.TableExpr
+	tx.Statement.Joins = stmt.Joins
+	tx = tx.Clauses(where)
+	if stmt.Unscoped {
+		tx = tx.Unscoped()
+	}
+
+	if c, ok := stmt.Clauses["FROM"]; ok {
+		// tables of UPDATE ... FROM are joined with the updating table
+		if from, ok := c.Expression.(clause.From); ok && (len(from.Tables) > 0 || len(from.Joins) > 0) {
+			from.Tables = append([]clause.Table{{Name: clause.CurrentTable}}, from.Tables...)
+			tx = tx.Clauses(from)
+		}
+	}
+
+	if tx.Statement.TableExpr != nil || len(tx.Statement.Joins) > 0 || tx.Statement.Clauses["FROM"].Expression != nil {
+		tx = tx.Select("?.*", clause.Table{Name: clause.CurrentTable})
+	}
+
+	if err := tx.Find(records.Interface()).Error; err != nil {
+		db.AddError(err)
+		return reflect.Value{}, false
+	}
+	return records.Elem(), true
+}
+
+// afterChange writes the logs collected before updating or deleting if any rows affected
+func (p *AuditPlugin) afterChange(db *DB) {
+	v, ok := db.InstanceGet(auditLogsKey)
+	if !ok {
+		return
+	}
+	db.Statement.Settings.Delete(fmt.Sprintf("%p", db.Statement) + auditLogsKey)
+
+	if p.auditable(db) && db.RowsAffected > 0 {
+		p.write(db, v.([]AuditLog))
+	}
+}
+
+// assignedValues returns the values assigned by current update statement, keyed by db name
+func (p *AuditPlugin) assignedValues(stmt *Statement) map[string]interface{} {
+	var (
+		values                    = map[string]interface{}{}
+		selectColumns, restricted = stmt.SelectAndOmitColumns(false, true)
+	)
+
+	switch dest := stmt.Dest.(type) {
+	case map[string]interface{}:
+		for key, value := range dest {
+			if field := stmt.Schema.LookUpField(key); field != nil && field.DBName != "" {
+				if v, ok := selectColumns[field.DBName]; (ok && v) || (!ok && !restricted) {
+					values[field.DBName] = p.auditValue(stmt, value)
+				}
+			}
+		}
+	default:
+		destValue := reflect.Indirect(reflect.ValueOf(dest))
+		if destValue.Kind() != reflect.Struct || destValue.Type() != stmt.Schema.ModelType {
+			return values
+		}
+
+		for _, dbName := range stmt.Schema.DBNames {
+			field := stmt.Schema.FieldsByDBName[dbName]
+			if field.PrimaryKey || !field.Updatable {
+				continue
+			}
+
+			value, zero := field.ValueOf(stmt.Context, destValue)
+			if v, ok := selectColumns[dbName]; (ok && v) || (!ok && !restricted && !zero) {
+				values[dbName] = value
+			}
+		}
+	}
+	return values
+}
+
+// changed reports whether assigning value to field changes the record, values can't be assigned, e.g: expressions,
+// are treated as changed
+func (p *AuditPlugin) changed(stmt *Statement, field *schema.Field, reflectValue reflect.Value, value interface{}) bool {
+	newValue := reflect.New(reflectValue.Type()).Elem()
+	if err := field.Set(stmt.Context, newValue, value); err != nil {
+		return true
+	}
+	return !utils.AssertEqual(field.ReflectValueOf(stmt.Context, reflectValue).Interface(), field.ReflectValueOf(stmt.Context, newValue).Interface())
+}
+
+// auditValue returns the SQL of expression values, e.g: `price + 5` for gorm.Expr("price + ?", 5)
+func (p *AuditPlugin) auditValue(stmt *Statement, value interface{}) interface{} {
+	if expr, ok := value.(clause.Expr); ok {
+		return stmt.Dialector.Explain(expr.SQL, expr.Vars...)
+	}
+	return value
+}
+
+func (p *AuditPlugin) session(db *DB) *DB {
+	return db.Session(&Session{NewDB: true, SkipHooks: true, Context: db.Statement.Context})
+}
+
+func (p *AuditPlugin) eachRecord(stmt *Statement, reflectValue reflect.Value, fc func(int, reflect.Value)) {
+	switch reflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < reflectValue.Len(); i++ {
+			if rv := reflect.Indirect(reflectValue.Index(i)); rv.Kind() == reflect.Struct {
+				fc(i, rv)
+			}
+		}
+	case reflect.Struct:
+		fc(0, reflectValue)
+	}
+}
+
+// primaryKey returns the primary key of the record, returns false if its primary keys are zero
+func (p *AuditPlugin) primaryKey(stmt *Statement, reflectValue reflect.Value) (string, bool) {
+	var (
+		values     = make([]interface{}, len(stmt.Schema.PrimaryFields))
+		identified bool
+	)
+
+	for idx, field := range stmt.Schema.PrimaryFields {
+		var zero bool
+		if values[idx], zero = field.ValueOf(stmt.Context, reflectValue); !zero {
+			identified = true
+		}
+	}
+	return utils.ToStringKey(values...), identified
+}
+
+func (p *AuditPlugin) valuesOf(stmt *Statement, reflectValue reflect.Value) map[string]interface{} {
+	values := make(map[string]interface{}, len(stmt.Schema.DBNames))
+	for _, dbName := range stmt.Schema.DBNames {
+		values[dbName] = stmt.Schema.FieldsByDBName[dbName].ReflectValueOf(stmt.Context, reflectValue).Interface()
+	}
+	return values
+}
+
+func (p *AuditPlugin) newLog(stmt *Statement, action, primaryKey string, oldValues, newValues map[string]interface{}) AuditLog {
+	return AuditLog{
+		Table:      stmt.Table,
+		PrimaryKey: primaryKey,
+		Action:     action,
+		Actor:      p.Actor(stmt.Context),
+		OldValues:  oldValues,
+		NewValues:  newValues,
+	}
+}
+
+func (p *AuditPlugin) write(db *DB, logs []AuditLog) {
+	if len(logs) > 0 {
+		db.AddError(p.session(db).Table(p.Table).Create(&logs).Error)
+	}
+}
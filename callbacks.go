@@ -15,12 +15,14 @@ import (
 func initializeCallbacks(db *DB) *callbacks {
 	return &callbacks{
 		processors: map[string]*processor{
-			"create": {db: db},
-			"query":  {db: db},
-			"update": {db: db},
-			"delete": {db: db},
-			"row":    {db: db},
-			"raw":    {db: db},
+			"create":  {db: db},
+			"query":   {db: db},
+			"update":  {db: db},
+			"delete":  {db: db},
+			"restore": {db: db},
+			"load":    {db: db},
+			"row":     {db: db},
+			"raw":     {db: db},
 		},
 	}
 }
@@ -64,6 +66,14 @@ func (cs *callbacks) Delete() *processor {
 	return cs.processors["delete"]
 }
 
+func (cs *callbacks) Restore() *processor {
+	return cs.processors["restore"]
+}
+
+func (cs *callbacks) Load() *processor {
+	return cs.processors["load"]
+}
+
 func (cs *callbacks) Row() *processor {
 	return cs.processors["row"]
 }
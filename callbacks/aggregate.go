@@ -0,0 +1,141 @@
+package callbacks
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
+)
+
+// buildAggregates adds correlated subqueries of WithCount, WithAggregate into select clause
+func buildAggregates(db *gorm.DB, clauseSelect clause.Select) {
+	if _, ok := db.Statement.Clauses["SELECT"]; ok || db.Statement.Schema == nil {
+		return
+	}
+
+	var (
+		aliases = map[string]bool{}
+		exprs   = make([]clause.Expression, 0, len(db.Statement.Aggregates)+1)
+	)
+
+	for _, aggregate := range db.Statement.Aggregates {
+		expr, alias, err := buildAggregate(db, aggregate.Name, aggregate.Function, aggregate.Column, aggregate.Conds)
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+
+		aliases[alias] = true
+		exprs = append(exprs, expr)
+	}
+
+	// aggregated fields are not columns of current table
+	if len(clauseSelect.Columns) > 0 {
+		columns := make([]clause.Column, 0, len(clauseSelect.Columns))
+		for _, column := range clauseSelect.Columns {
+			if column.Raw || !aliases[column.Name] || (column.Table != "" && column.Table != clause.CurrentTable && column.Table != db.Statement.Table) {
+				columns = append(columns, column)
+			}
+		}
+		clauseSelect.Columns = columns
+
+		if len(columns) > 0 {
+			exprs = append([]clause.Expression{clauseSelect}, exprs...)
+		}
+	} else {
+		sql := "?.*"
+		if clauseSelect.Distinct {
+			sql = "DISTINCT " + sql
+		}
+		// the alias of current table if aliased, e.g: `db.Table("users u")`
+		exprs = append([]clause.Expression{clause.Expr{SQL: sql, Vars: []interface{}{clause.Table{Name: db.Statement.Table}}}}, exprs...)
+	}
+
+	db.Statement.AddClause(clause.Select{Expression: clause.CommaExpression{Exprs: exprs}})
+}
+
+// buildAggregate builds subquery like `(SELECT COUNT(*) FROM orders Orders WHERE Orders.user_id = users.id) AS orders_count`
+func buildAggregate(db *gorm.DB, name, function, column string, conds []interface{}) (clause.Expression, string, error) {
+	rel := db.Statement.Schema.Relationships.Relations[name]
+	if rel == nil || (rel.Type != schema.HasOne && rel.Type != schema.HasMany && rel.Type != schema.Many2Many) {
+		return nil, "", fmt.Errorf("%s: %w for schema %s", name, gorm.ErrUnsupportedRelation, db.Statement.Schema.Name)
+	}
+
+	function = strings.ToLower(function)
+	switch function {
+	case "count", "sum", "avg", "min", "max":
+	default:
+		return nil, "", fmt.Errorf("%s: %w", function, gorm.ErrUnsupportedAggregate)
+	}
+
+	var (
+		alias      string
+		aggregated interface{} = clause.Expr{SQL: "*"}
+	)
+
+	if column == "" && function == "count" {
+		alias = db.NamingStrategy.ColumnName("", rel.Name+"Count")
+	} else if field := rel.FieldSchema.LookUpField(column); field != nil && field.DBName != "" {
+		aggregated = clause.Column{Table: rel.Name, Name: field.DBName}
+		alias = db.NamingStrategy.ColumnName("", rel.Name+strings.ToUpper(function[:1])+function[1:]+field.Name)
+	} else {
+		return nil, "", fmt.Errorf("%s: %w for schema %s", column, gorm.ErrInvalidField, rel.FieldSchema.Name)
+	}
+
+	// tables of the subquery are aliased by the relation name like joins, e.g: `orders Orders`, so they are distinguished
+	// from the outer table of self-referential relations
+	var (
+		tx            = db.Session(&gorm.Session{NewDB: true}).Model(reflect.New(rel.FieldSchema.ModelType).Interface())
+		table         = rel.Name
+		joinTableName string
+		exprs         = make([]clause.Expression, 0, len(rel.References))
+		joinConds     = make([]clause.Expression, 0, len(rel.References))
+		inlineConds   []interface{}
+	)
+
+	tx.Statement.Table = rel.Name
+	tx.Statement.TableExpr = &clause.Expr{SQL: "? ?", Vars: []interface{}{clause.Table{Name: rel.FieldSchema.Table}, clause.Table{Name: rel.Name}}}
+	if rel.JoinTable != nil {
+		joinTableName = utils.NestedRelationName(rel.Name, rel.JoinTable.Name)
+		table = joinTableName
+	}
+
+	for _, ref := range rel.References {
+		if ref.OwnPrimaryKey {
+			exprs = append(exprs, clause.Eq{
+				Column: clause.Column{Table: table, Name: ref.ForeignKey.DBName},
+				Value:  clause.Column{Table: db.Statement.Table, Name: ref.PrimaryKey.DBName},
+			})
+		} else if ref.PrimaryValue != "" {
+			exprs = append(exprs, clause.Eq{Column: clause.Column{Table: table, Name: ref.ForeignKey.DBName}, Value: ref.PrimaryValue})
+		} else {
+			joinConds = append(joinConds, clause.Eq{
+				Column: clause.Column{Table: joinTableName, Name: ref.ForeignKey.DBName},
+				Value:  clause.Column{Table: rel.Name, Name: ref.PrimaryKey.DBName},
+			})
+		}
+	}
+
+	if rel.JoinTable != nil {
+		tx = tx.Joins("INNER JOIN ? ON ?", clause.Table{Name: rel.JoinTable.Table, Alias: joinTableName}, clause.And(joinConds...))
+	}
+
+	for _, cond := range conds {
+		if fc, ok := cond.(func(*gorm.DB) *gorm.DB); ok {
+			tx = fc(tx)
+		} else {
+			inlineConds = append(inlineConds, cond)
+		}
+	}
+
+	tx = tx.Select(strings.ToUpper(function)+"(?)", aggregated).Where(clause.And(exprs...))
+	if len(inlineConds) > 0 {
+		tx = tx.Where(inlineConds[0], inlineConds[1:]...)
+	}
+
+	return clause.Expr{SQL: "(?) AS ?", Vars: []interface{}{tx, clause.Column{Name: alias}}}, alias, nil
+}
@@ -1,6 +1,7 @@
 package callbacks
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 
@@ -199,9 +200,13 @@ func SaveAfterAssociations(create bool) func(db *gorm.DB) {
 				}
 				elems := reflect.MakeSlice(reflect.SliceOf(fieldType), 0, 10)
 				identityMap := map[string]bool{}
+				positionField := rel.PositionField()
 				appendToElems := func(v reflect.Value) {
 					if _, zero := rel.Field.ValueOf(db.Statement.Context, v); !zero {
 						f := reflect.Indirect(rel.Field.ReflectValueOf(db.Statement.Context, v))
+						if positionField != nil {
+							assignPositions(db, rel, positionField, f)
+						}
 
 						for i := 0; i < f.Len(); i++ {
 							elem := f.Index(i)
@@ -250,11 +255,15 @@ func SaveAfterAssociations(create bool) func(db *gorm.DB) {
 				}
 
 				if elems.Len() > 0 {
-					assignmentColumns := make([]string, 0, len(rel.References))
+					assignmentColumns := make([]string, 0, len(rel.References)+1)
 					for _, ref := range rel.References {
 						assignmentColumns = append(assignmentColumns, ref.ForeignKey.DBName)
 					}
 
+					if positionField != nil {
+						assignmentColumns = append(assignmentColumns, positionField.DBName)
+					}
+
 					saveAssociations(db, rel, elems, selectColumns, restricted, assignmentColumns)
 				}
 			}
@@ -273,10 +282,41 @@ func SaveAfterAssociations(create bool) func(db *gorm.DB) {
 				elems := reflect.MakeSlice(reflect.SliceOf(fieldType), 0, 10)
 				distinctElems := reflect.MakeSlice(reflect.SliceOf(fieldType), 0, 10)
 				joins := reflect.MakeSlice(reflect.SliceOf(reflect.PtrTo(rel.JoinTable.ModelType)), 0, 10)
+				pivotJoins := reflect.MakeSlice(reflect.SliceOf(reflect.PtrTo(rel.JoinTable.ModelType)), 0, 10)
 				objs := []reflect.Value{}
+				pivotField := rel.PivotField()
+				pivots, _ := db.Get("gorm:pivots")
+				positionField := rel.PositionField()
+				positions := []int{}
 
-				appendToJoins := func(obj reflect.Value, elem reflect.Value) {
+				appendToJoins := func(obj reflect.Value, elem reflect.Value, position int) {
 					joinValue := reflect.New(rel.JoinTable.ModelType)
+					if positionField != nil && positionField.Schema == rel.JoinTable {
+						// positions default to the index of elem, join model values could override it
+						db.AddError(positionField.Set(db.Statement.Context, joinValue, position))
+					}
+
+					// join model values from WithPivot keyed by the address of obj and the index of elem, or the pivot field of elem
+					var pivot interface{}
+					if values, ok := pivots.(map[string]interface{}); ok && len(values) > 0 {
+						if source := reflect.Indirect(obj); source.CanAddr() {
+							pivot = values[utils.ToStringKey(source.Addr().Pointer(), position)]
+						}
+					}
+					if pivotField != nil && pivot == nil {
+						if v, zero := pivotField.ValueOf(db.Statement.Context, elem); !zero {
+							pivot = v
+						}
+					}
+
+					if pivot != nil {
+						if pv := reflect.Indirect(reflect.ValueOf(pivot)); pv.Type() == rel.JoinTable.ModelType {
+							joinValue.Elem().Set(pv)
+						} else {
+							db.AddError(fmt.Errorf("invalid pivot %v for relation %s, should be %v", pv.Type(), rel.Name, rel.JoinTable.ModelType))
+						}
+					}
+
 					for _, ref := range rel.References {
 						if ref.OwnPrimaryKey {
 							fv, _ := ref.PrimaryKey.ValueOf(db.Statement.Context, obj)
@@ -288,7 +328,12 @@ func SaveAfterAssociations(create bool) func(db *gorm.DB) {
 							db.AddError(ref.ForeignKey.Set(db.Statement.Context, joinValue, fv))
 						}
 					}
-					joins = reflect.Append(joins, joinValue)
+
+					if pivot != nil {
+						pivotJoins = reflect.Append(pivotJoins, joinValue)
+					} else {
+						joins = reflect.Append(joins, joinValue)
+					}
 				}
 
 				identityMap := map[string]bool{}
@@ -301,6 +346,7 @@ func SaveAfterAssociations(create bool) func(db *gorm.DB) {
 								elem = elem.Addr()
 							}
 							objs = append(objs, v)
+							positions = append(positions, i)
 							elems = reflect.Append(elems, elem)
 
 							relPrimaryValues := make([]interface{}, 0, len(rel.FieldSchema.PrimaryFields))
@@ -342,16 +388,77 @@ func SaveAfterAssociations(create bool) func(db *gorm.DB) {
 					}
 
 					for i := 0; i < elemLen; i++ {
-						appendToJoins(objs[i], elems.Index(i))
+						appendToJoins(objs[i], elems.Index(i), positions[i])
 					}
 				}
 
+				if joins.Len() > 0 && len(rel.JoinTable.RestoreClauses) > 0 {
+					joins = reviveJoins(db, rel, joins)
+				}
+
+				// join records of join table with surrogate primary key are found by foreign keys, existing ones are
+				// skipped or updated with join model values, soft deleted ones are revived by the update
+				var existingPivotJoins reflect.Value
+				if !isKeyedByForeignKeys(rel) {
+					joins, _ = splitExistingJoins(db, rel, joins)
+					pivotJoins, existingPivotJoins = splitExistingJoins(db, rel, pivotJoins)
+				}
+
 				if joins.Len() > 0 {
 					db.AddError(db.Session(&gorm.Session{NewDB: true}).Clauses(clause.OnConflict{DoNothing: true}).Session(&gorm.Session{
 						SkipHooks:                db.Statement.SkipHooks,
 						DisableNestedTransaction: true,
 					}).Create(joins.Interface()).Error)
 				}
+
+				// update join model values of existing join records
+				if pivotJoins.Len() > 0 {
+					onConflict := clause.OnConflict{DoNothing: true}
+					for _, field := range rel.JoinTable.PrimaryFields {
+						onConflict.Columns = append(onConflict.Columns, clause.Column{Name: field.DBName})
+					}
+
+					var columns []string
+					for _, field := range rel.JoinTable.Fields {
+						if field.DBName != "" && !field.PrimaryKey && field.AutoCreateTime == 0 && field.Updatable {
+							columns = append(columns, field.DBName)
+						}
+					}
+
+					if len(columns) > 0 && len(onConflict.Columns) > 0 {
+						onConflict.DoNothing = false
+						onConflict.DoUpdates = clause.AssignmentColumns(columns)
+					}
+
+					db.AddError(db.Session(&gorm.Session{NewDB: true}).Clauses(onConflict).Session(&gorm.Session{
+						SkipHooks:                db.Statement.SkipHooks,
+						DisableNestedTransaction: true,
+					}).Create(pivotJoins.Interface()).Error)
+				}
+
+				for i := 0; existingPivotJoins.IsValid() && i < existingPivotJoins.Len(); i++ {
+					joinValue := existingPivotJoins.Index(i)
+					conds := make([]clause.Expression, 0, len(rel.References))
+					for _, ref := range rel.References {
+						fv, _ := ref.ForeignKey.ValueOf(db.Statement.Context, joinValue)
+						conds = append(conds, clause.Eq{Column: clause.Column{Table: rel.JoinTable.Table, Name: ref.ForeignKey.DBName}, Value: fv})
+					}
+
+					var columns []string
+					for _, field := range rel.JoinTable.Fields {
+						if field.DBName != "" && !field.PrimaryKey && field.AutoCreateTime == 0 && field.Updatable {
+							columns = append(columns, field.DBName)
+						}
+					}
+
+					if len(columns) > 0 {
+						db.AddError(db.Session(&gorm.Session{
+							NewDB:                    true,
+							SkipHooks:                db.Statement.SkipHooks,
+							DisableNestedTransaction: true,
+						}).Unscoped().Model(reflect.New(rel.JoinTable.ModelType).Interface()).Where(clause.And(conds...)).Select(columns).Updates(joinValue.Interface()).Error)
+					}
+				}
 			}
 		}
 	}
@@ -451,3 +558,178 @@ func checkAssociationsSaved(db *gorm.DB, values reflect.Value) bool {
 
 	return false
 }
+
+// reviveJoins restores the soft deleted join records of joins, returns the joins whose records don't exist
+func reviveJoins(db *gorm.DB, rel *schema.Relationship, joins reflect.Value) reflect.Value {
+	var (
+		foreignKeys   = make([]string, 0, len(rel.References))
+		foreignFields = make([]*schema.Field, 0, len(rel.References))
+		joinValues    = make([][]interface{}, 0, joins.Len())
+		existingMap   = map[string]bool{}
+		existing      = rel.JoinTable.MakeSlice()
+		tx            = db.Session(&gorm.Session{
+			NewDB:                    true,
+			SkipHooks:                db.Statement.SkipHooks,
+			DisableNestedTransaction: true,
+		})
+	)
+
+	for _, ref := range rel.References {
+		foreignKeys = append(foreignKeys, ref.ForeignKey.DBName)
+		foreignFields = append(foreignFields, ref.ForeignKey)
+	}
+
+	keyValuesOf := func(rv reflect.Value) []interface{} {
+		values := make([]interface{}, len(foreignFields))
+		for idx, field := range foreignFields {
+			values[idx], _ = field.ValueOf(db.Statement.Context, rv)
+		}
+		return values
+	}
+
+	for i := 0; i < joins.Len(); i++ {
+		joinValues = append(joinValues, keyValuesOf(joins.Index(i)))
+	}
+
+	column, values := schema.ToQueryValues(rel.JoinTable.Table, foreignKeys, joinValues)
+	if db.AddError(tx.Unscoped().Table(rel.JoinTable.Table).Where(clause.IN{Column: column, Values: values}).Find(existing.Interface()).Error) != nil {
+		return joins
+	}
+
+	if existing.Elem().Len() == 0 {
+		return joins
+	}
+
+	existingValues := make([][]interface{}, 0, existing.Elem().Len())
+	for i := 0; i < existing.Elem().Len(); i++ {
+		keyValues := keyValuesOf(existing.Elem().Index(i))
+		existingMap[utils.ToStringKey(keyValues...)] = true
+		existingValues = append(existingValues, keyValues)
+	}
+
+	modelValue := reflect.New(rel.JoinTable.ModelType).Interface()
+	column, values = schema.ToQueryValues(rel.JoinTable.Table, foreignKeys, existingValues)
+	if db.AddError(tx.Model(modelValue).Table(rel.JoinTable.Table).Where(clause.IN{Column: column, Values: values}).Restore(modelValue).Error) != nil {
+		return joins
+	}
+
+	missingJoins := reflect.MakeSlice(joins.Type(), 0, joins.Len())
+	for i := 0; i < joins.Len(); i++ {
+		if !existingMap[utils.ToStringKey(joinValues[i]...)] {
+			missingJoins = reflect.Append(missingJoins, joins.Index(i))
+		}
+	}
+	return missingJoins
+}
+
+// isKeyedByForeignKeys returns true if the primary keys of join table are its foreign keys, e.g: `user_id`, `language_code`
+func isKeyedByForeignKeys(rel *schema.Relationship) bool {
+	foreignKeys := map[string]bool{}
+	for _, ref := range rel.References {
+		foreignKeys[ref.ForeignKey.DBName] = true
+	}
+
+	for _, field := range rel.JoinTable.PrimaryFields {
+		if !foreignKeys[field.DBName] {
+			return false
+		}
+	}
+	return len(rel.JoinTable.PrimaryFields) > 0
+}
+
+// splitExistingJoins splits join records into the missing ones and the existing ones found by the foreign keys of join
+// table, including soft deleted ones
+func splitExistingJoins(db *gorm.DB, rel *schema.Relationship, joins reflect.Value) (missing, existing reflect.Value) {
+	missing = reflect.MakeSlice(joins.Type(), 0, joins.Len())
+	existing = reflect.MakeSlice(joins.Type(), 0, joins.Len())
+	if joins.Len() == 0 {
+		return joins, existing
+	}
+
+	var (
+		foreignKeys   = make([]string, 0, len(rel.References))
+		foreignFields = make([]*schema.Field, 0, len(rel.References))
+		joinValues    = make([][]interface{}, 0, joins.Len())
+		existingMap   = map[string]bool{}
+		records       = rel.JoinTable.MakeSlice()
+	)
+
+	for _, ref := range rel.References {
+		foreignKeys = append(foreignKeys, ref.ForeignKey.DBName)
+		foreignFields = append(foreignFields, ref.ForeignKey)
+	}
+
+	keyValuesOf := func(rv reflect.Value) []interface{} {
+		values := make([]interface{}, len(foreignFields))
+		for idx, field := range foreignFields {
+			values[idx], _ = field.ValueOf(db.Statement.Context, rv)
+		}
+		return values
+	}
+
+	for i := 0; i < joins.Len(); i++ {
+		joinValues = append(joinValues, keyValuesOf(joins.Index(i)))
+	}
+
+	column, values := schema.ToQueryValues(rel.JoinTable.Table, foreignKeys, joinValues)
+	if db.AddError(db.Session(&gorm.Session{NewDB: true}).Unscoped().Table(rel.JoinTable.Table).Where(clause.IN{Column: column, Values: values}).Find(records.Interface()).Error) != nil {
+		return joins, existing
+	}
+
+	for i := 0; i < records.Elem().Len(); i++ {
+		existingMap[utils.ToStringKey(keyValuesOf(records.Elem().Index(i))...)] = true
+	}
+
+	for i := 0; i < joins.Len(); i++ {
+		if existingMap[utils.ToStringKey(joinValues[i]...)] {
+			existing = reflect.Append(existing, joins.Index(i))
+		} else {
+			missing = reflect.Append(missing, joins.Index(i))
+		}
+	}
+	return missing, existing
+}
+
+// assignPositions assigns positions to the new records without position of positioned has many relation, positions
+// are numbered after the max position of other records, existing records and records with position are kept
+func assignPositions(db *gorm.DB, rel *schema.Relationship, positionField *schema.Field, records reflect.Value) {
+	var (
+		ctx          = db.Statement.Context
+		next         int64
+		unpositioned []reflect.Value
+	)
+
+	for i := 0; i < records.Len(); i++ {
+		elem := records.Index(i)
+		position, zero := positionField.ValueOf(ctx, elem)
+		if zero {
+			isNew := true
+			for _, field := range rel.FieldSchema.PrimaryFields {
+				if _, isZero := field.ValueOf(ctx, elem); !isZero {
+					isNew = false
+				}
+			}
+
+			if isNew {
+				unpositioned = append(unpositioned, elem)
+				continue
+			}
+		}
+
+		switch rv := reflect.Indirect(reflect.ValueOf(position)); rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if rv.Int() >= next {
+				next = rv.Int() + 1
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if int64(rv.Uint()) >= next {
+				next = int64(rv.Uint()) + 1
+			}
+		}
+	}
+
+	for _, elem := range unpositioned {
+		db.AddError(positionField.Set(ctx, elem, next))
+		next++
+	}
+}
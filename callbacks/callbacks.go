@@ -40,6 +40,7 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 	createCallback := db.Callback().Create()
 	createCallback.Match(enableTransaction).Register("gorm:begin_transaction", BeginTransaction)
 	createCallback.Register("gorm:before_create", BeforeCreate)
+	createCallback.Register("gorm:validate", Validate(true))
 	createCallback.Register("gorm:save_before_associations", SaveBeforeAssociations(true))
 	createCallback.Register("gorm:create", Create(config))
 	createCallback.Register("gorm:save_after_associations", SaveAfterAssociations(true))
@@ -53,6 +54,10 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 	queryCallback.Register("gorm:after_query", AfterQuery)
 	queryCallback.Clauses = config.QueryClauses
 
+	loadCallback := db.Callback().Load()
+	loadCallback.Register("gorm:preload", Preload)
+	loadCallback.Clauses = config.QueryClauses
+
 	deleteCallback := db.Callback().Delete()
 	deleteCallback.Match(enableTransaction).Register("gorm:begin_transaction", BeginTransaction)
 	deleteCallback.Register("gorm:before_delete", BeforeDelete)
@@ -62,10 +67,20 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 	deleteCallback.Match(enableTransaction).Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 	deleteCallback.Clauses = config.DeleteClauses
 
+	restoreCallback := db.Callback().Restore()
+	restoreCallback.Match(enableTransaction).Register("gorm:begin_transaction", BeginTransaction)
+	restoreCallback.Register("gorm:before_restore", BeforeRestore)
+	restoreCallback.Register("gorm:restore_before_associations", RestoreBeforeAssociations)
+	restoreCallback.Register("gorm:restore", Restore)
+	restoreCallback.Register("gorm:after_restore", AfterRestore)
+	restoreCallback.Match(enableTransaction).Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
+	restoreCallback.Clauses = config.UpdateClauses
+
 	updateCallback := db.Callback().Update()
 	updateCallback.Match(enableTransaction).Register("gorm:begin_transaction", BeginTransaction)
 	updateCallback.Register("gorm:setup_reflect_value", SetupUpdateReflectValue)
 	updateCallback.Register("gorm:before_update", BeforeUpdate)
+	updateCallback.Register("gorm:validate", Validate(false))
 	updateCallback.Register("gorm:save_before_associations", SaveBeforeAssociations(false))
 	updateCallback.Register("gorm:update", Update(config))
 	updateCallback.Register("gorm:save_after_associations", SaveAfterAssociations(false))
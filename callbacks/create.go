@@ -43,6 +43,8 @@ func Create(config *Config) func(db *gorm.DB) {
 		}
 
 		if db.Statement.Schema != nil {
+			setDiscriminator(db)
+
 			if !db.Statement.Unscoped {
 				for _, c := range db.Statement.Schema.CreateClauses {
 					db.Statement.AddClause(c)
@@ -73,6 +75,11 @@ func Create(config *Config) func(db *gorm.DB) {
 			return
 		}
 
+		if !supportReturning {
+			// read back generated fields after primary keys assigned
+			defer reloadGeneratedFields(db)
+		}
+
 		ok, mode := hasReturning(db, supportReturning)
 		if ok {
 			if c, ok := db.Statement.Clauses["ON CONFLICT"]; ok {
@@ -268,7 +275,14 @@ func ConvertToCreateValues(stmt *gorm.Statement) (values clause.Values) {
 				for idx, column := range values.Columns {
 					field := stmt.Schema.FieldsByDBName[column.Name]
 					if values.Values[i][idx], isZero = field.ValueOf(stmt.Context, rv); isZero {
-						if field.DefaultValueInterface != nil {
+						if field.Generator != nil {
+							if value, err := field.Generator.Generate(stmt.Context, field); err == nil {
+								stmt.AddError(field.Set(stmt.Context, rv, value))
+								values.Values[i][idx], _ = field.ValueOf(stmt.Context, rv)
+							} else {
+								stmt.AddError(err)
+							}
+						} else if field.DefaultValueInterface != nil {
 							values.Values[i][idx] = field.DefaultValueInterface
 							stmt.AddError(field.Set(stmt.Context, rv, field.DefaultValueInterface))
 						} else if field.AutoCreateTime > 0 || field.AutoUpdateTime > 0 {
@@ -308,7 +322,14 @@ func ConvertToCreateValues(stmt *gorm.Statement) (values clause.Values) {
 			for idx, column := range values.Columns {
 				field := stmt.Schema.FieldsByDBName[column.Name]
 				if values.Values[0][idx], isZero = field.ValueOf(stmt.Context, stmt.ReflectValue); isZero {
-					if field.DefaultValueInterface != nil {
+					if field.Generator != nil {
+						if value, err := field.Generator.Generate(stmt.Context, field); err == nil {
+							stmt.AddError(field.Set(stmt.Context, stmt.ReflectValue, value))
+							values.Values[0][idx], _ = field.ValueOf(stmt.Context, stmt.ReflectValue)
+						} else {
+							stmt.AddError(err)
+						}
+					} else if field.DefaultValueInterface != nil {
 						values.Values[0][idx] = field.DefaultValueInterface
 						stmt.AddError(field.Set(stmt.Context, stmt.ReflectValue, field.DefaultValueInterface))
 					} else if field.AutoCreateTime > 0 || field.AutoUpdateTime > 0 {
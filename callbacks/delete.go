@@ -25,44 +25,62 @@ func BeforeDelete(db *gorm.DB) {
 
 func DeleteBeforeAssociations(db *gorm.DB) {
 	if db.Error == nil && db.Statement.Schema != nil {
-		selectColumns, restricted := db.Statement.SelectAndOmitColumns(true, false)
-		if !restricted {
-			return
-		}
+		var (
+			selectColumns, restricted = db.Statement.SelectAndOmitColumns(true, false)
+			relations                 []*schema.Relationship
+			reflectValue              = db.Statement.ReflectValue
+		)
 
 		for column, v := range selectColumns {
-			if !v {
-				continue
+			if rel, ok := db.Statement.Schema.Relationships.Relations[column]; ok && v && restricted {
+				relations = append(relations, rel)
 			}
+		}
 
-			rel, ok := db.Statement.Schema.Relationships.Relations[column]
-			if !ok {
-				continue
+		// soft delete relations with `onSoftDelete:cascade` together with their owner
+		if !db.Statement.Unscoped && len(db.Statement.Schema.RestoreClauses) > 0 {
+			var cascades int
+			for _, rel := range db.Statement.Schema.Relationships.Relations {
+				if _, ok := selectColumns[rel.Name]; !ok && rel.Schema == db.Statement.Schema && softDeleteCascade(rel) {
+					relations = append(relations, rel)
+					cascades++
+				}
+			}
+
+			if len(relations) == 0 {
+				return
+			}
+
+			if _, ok := db.Get(gorm.SoftDeleteTimeKey); !ok {
+				// shared by the owner and the cascade of current deletion, cleared after deleting
+				db.Statement.Settings.Store(gorm.SoftDeleteTimeKey, db.Statement.DB.NowFunc())
+				db.Statement.Settings.Store(cascadeSoftDeleteTimeKey, true)
+			}
+
+			if cascades > 0 {
+				if _, values := schema.GetIdentityFieldValuesMap(db.Statement.Context, reflectValue, db.Statement.Schema.PrimaryFields); len(values) == 0 {
+					records, ok := findStatementRecords(db, false)
+					if !ok {
+						return
+					}
+					reflectValue = records
+				}
 			}
+		}
 
+		for _, rel := range relations {
 			switch rel.Type {
 			case schema.HasOne, schema.HasMany:
-				queryConds := rel.ToQueryConditions(db.Statement.Context, db.Statement.ReflectValue)
+				queryConds := rel.ToQueryConditions(db.Statement.Context, reflectValue)
 				modelValue := reflect.New(rel.FieldSchema.ModelType).Interface()
-				tx := db.Session(&gorm.Session{NewDB: true}).Model(modelValue)
+				tx := associationSession(db).Model(modelValue)
 				withoutConditions := false
 				if db.Statement.Unscoped {
 					tx = tx.Unscoped()
 				}
 
-				if len(db.Statement.Selects) > 0 {
-					selects := make([]string, 0, len(db.Statement.Selects))
-					for _, s := range db.Statement.Selects {
-						if s == clause.Associations {
-							selects = append(selects, s)
-						} else if columnPrefix := column + "."; strings.HasPrefix(s, columnPrefix) {
-							selects = append(selects, strings.TrimPrefix(s, columnPrefix))
-						}
-					}
-
-					if len(selects) > 0 {
-						tx = tx.Select(selects)
-					}
+				if selects := associationSelects(db, rel.Name); len(selects) > 0 {
+					tx = tx.Select(selects)
 				}
 
 				for _, cond := range queryConds {
@@ -77,48 +95,103 @@ func DeleteBeforeAssociations(db *gorm.DB) {
 				}
 			case schema.Many2Many:
 				var (
-					queryConds     = make([]clause.Expression, 0, len(rel.References))
-					foreignFields  = make([]*schema.Field, 0, len(rel.References))
-					relForeignKeys = make([]string, 0, len(rel.References))
-					modelValue     = reflect.New(rel.JoinTable.ModelType).Interface()
-					table          = rel.JoinTable.Table
-					tx             = db.Session(&gorm.Session{NewDB: true}).Model(modelValue).Table(table)
+					modelValue = reflect.New(rel.JoinTable.ModelType).Interface()
+					tx         = associationSession(db).Model(modelValue).Table(rel.JoinTable.Table)
 				)
 
-				for _, ref := range rel.References {
-					if ref.OwnPrimaryKey {
-						foreignFields = append(foreignFields, ref.PrimaryKey)
-						relForeignKeys = append(relForeignKeys, ref.ForeignKey.DBName)
-					} else if ref.PrimaryValue != "" {
-						queryConds = append(queryConds, clause.Eq{
-							Column: clause.Column{Table: rel.JoinTable.Table, Name: ref.ForeignKey.DBName},
-							Value:  ref.PrimaryValue,
-						})
-					}
-				}
-
-				_, foreignValues := schema.GetIdentityFieldValuesMap(db.Statement.Context, db.Statement.ReflectValue, foreignFields)
-				column, values := schema.ToQueryValues(table, relForeignKeys, foreignValues)
-				queryConds = append(queryConds, clause.IN{Column: column, Values: values})
-
-				if db.AddError(tx.Clauses(clause.Where{Exprs: queryConds}).Delete(modelValue).Error) != nil {
+				if db.AddError(tx.Clauses(clause.Where{Exprs: joinTableConditions(db, rel, reflectValue)}).Delete(modelValue).Error) != nil {
 					return
 				}
 			}
 		}
+	}
+}
+
+// softDeleteCascade returns true if the relation is tagged with `onSoftDelete:cascade` and could be soft deleted
+func softDeleteCascade(rel *schema.Relationship) bool {
+	if !strings.EqualFold(rel.Field.TagSettings["ONSOFTDELETE"], "cascade") {
+		return false
+	}
+
+	switch rel.Type {
+	case schema.HasOne, schema.HasMany:
+		return len(rel.FieldSchema.RestoreClauses) > 0
+	case schema.Many2Many:
+		return len(rel.JoinTable.RestoreClauses) > 0
+	}
+	return false
+}
+
+// cascadeSoftDeleteTimeKey marks the soft delete time stored for the cascade of current deletion
+const cascadeSoftDeleteTimeKey = "gorm:cascade_soft_delete_time"
+
+// clearCascadeSoftDeleteTime clears the soft delete time stored for the cascade, so it is not reused by later deletions
+// of the same statement, e.g: `tx := db.Where(...); tx.Delete(&a); tx.Delete(&b)`
+func clearCascadeSoftDeleteTime(db *gorm.DB) {
+	if _, ok := db.Statement.Settings.LoadAndDelete(cascadeSoftDeleteTimeKey); ok {
+		db.Statement.Settings.Delete(gorm.SoftDeleteTimeKey)
+	}
+}
 
+// associationSession returns a new session for associations, which shares the soft delete time with current statement
+func associationSession(db *gorm.DB) *gorm.DB {
+	tx := db.Session(&gorm.Session{NewDB: true})
+	if deletedAt, ok := db.Get(gorm.SoftDeleteTimeKey); ok {
+		tx = tx.Set(gorm.SoftDeleteTimeKey, deletedAt)
+	}
+	return tx
+}
+
+// joinTableConditions returns the conditions of join table records that belongs to reflectValue
+func joinTableConditions(db *gorm.DB, rel *schema.Relationship, reflectValue reflect.Value) []clause.Expression {
+	var (
+		queryConds     = make([]clause.Expression, 0, len(rel.References))
+		foreignFields  = make([]*schema.Field, 0, len(rel.References))
+		relForeignKeys = make([]string, 0, len(rel.References))
+		table          = rel.JoinTable.Table
+	)
+
+	for _, ref := range rel.References {
+		if ref.OwnPrimaryKey {
+			foreignFields = append(foreignFields, ref.PrimaryKey)
+			relForeignKeys = append(relForeignKeys, ref.ForeignKey.DBName)
+		} else if ref.PrimaryValue != "" {
+			queryConds = append(queryConds, clause.Eq{
+				Column: clause.Column{Table: rel.JoinTable.Table, Name: ref.ForeignKey.DBName},
+				Value:  ref.PrimaryValue,
+			})
+		}
+	}
+
+	_, foreignValues := schema.GetIdentityFieldValuesMap(db.Statement.Context, reflectValue, foreignFields)
+	column, values := schema.ToQueryValues(table, relForeignKeys, foreignValues)
+	return append(queryConds, clause.IN{Column: column, Values: values})
+}
+
+// associationSelects returns the selected columns of the association from statement's Selects
+func associationSelects(db *gorm.DB, column string) []string {
+	selects := make([]string, 0, len(db.Statement.Selects))
+	for _, s := range db.Statement.Selects {
+		if s == clause.Associations {
+			selects = append(selects, s)
+		} else if columnPrefix := column + "."; strings.HasPrefix(s, columnPrefix) {
+			selects = append(selects, strings.TrimPrefix(s, columnPrefix))
+		}
 	}
+	return selects
 }
 
 func Delete(config *Config) func(db *gorm.DB) {
 	supportReturning := utils.Contains(config.DeleteClauses, "RETURNING")
 
 	return func(db *gorm.DB) {
+		defer clearCascadeSoftDeleteTime(db)
 		if db.Error != nil {
 			return
 		}
 
 		if db.Statement.Schema != nil {
+			addDiscriminatorCondition(db)
 			for _, c := range db.Statement.Schema.DeleteClauses {
 				db.Statement.AddClause(c)
 			}
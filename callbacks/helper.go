@@ -6,6 +6,8 @@ import (
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
 )
 
 // ConvertMapToValuesForCreate convert map to values
@@ -110,9 +112,18 @@ func checkMissingWhereConditions(db *gorm.DB) {
 	if !db.AllowGlobalUpdate && db.Error == nil {
 		where, withCondition := db.Statement.Clauses["WHERE"]
 		if withCondition {
+			// conditions of soft delete and single table inheritance are not counted
+			var builtinConds int
 			if _, withSoftDelete := db.Statement.Clauses["soft_delete_enabled"]; withSoftDelete {
+				builtinConds++
+			}
+			if _, withInheritance := db.Statement.Clauses[inheritanceEnabled]; withInheritance {
+				builtinConds++
+			}
+
+			if builtinConds > 0 {
 				whereClause, _ := where.Expression.(clause.Where)
-				withCondition = len(whereClause.Exprs) > 1
+				withCondition = len(whereClause.Exprs) > builtinConds
 			}
 		}
 		if !withCondition {
@@ -122,6 +133,99 @@ func checkMissingWhereConditions(db *gorm.DB) {
 	}
 }
 
+// findStatementRecords finds records matching current statement's conditions and primary keys
+func findStatementRecords(db *gorm.DB, unscoped bool, exprs ...clause.Expression) (reflect.Value, bool) {
+	var (
+		stmt  = db.Statement
+		conds []clause.Expression
+	)
+
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok {
+			conds = append(conds, where.Exprs...)
+		}
+	}
+
+	_, queryValues := schema.GetIdentityFieldValuesMap(stmt.Context, stmt.ReflectValue, stmt.Schema.PrimaryFields)
+	if column, values := schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, queryValues); len(values) > 0 {
+		conds = append(conds, clause.IN{Column: column, Values: values})
+	}
+
+	if len(conds) == 0 && !db.AllowGlobalUpdate {
+		return reflect.Value{}, false
+	}
+	exprs = append(exprs, conds...)
+
+	records := stmt.Schema.MakeSlice()
+	tx := db.Session(&gorm.Session{NewDB: true}).Table(stmt.Table)
+	if unscoped {
+		tx = tx.Unscoped()
+	}
+
+	if err := tx.Clauses(clause.Where{Exprs: exprs}).Find(records.Interface()).Error; err != nil {
+		db.AddError(err)
+		return reflect.Value{}, false
+	}
+	return records.Elem(), true
+}
+
+// reloadGeneratedFields reads back generated fields of created, updated records by primary keys, used when RETURNING
+// is unsupported
+func reloadGeneratedFields(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || db.RowsAffected == 0 || stmt.Schema == nil || len(stmt.Schema.PrimaryFields) == 0 {
+		return
+	}
+
+	var (
+		columns []interface{}
+		fields  []*schema.Field
+	)
+	for _, field := range stmt.Schema.Fields {
+		if field.GeneratedExpression != "" && field.DBName != "" && field.Readable {
+			fields = append(fields, field)
+			columns = append(columns, field.DBName)
+		}
+	}
+
+	if len(fields) == 0 || (stmt.ReflectValue.Kind() == reflect.Struct && !stmt.ReflectValue.CanAddr()) {
+		return
+	}
+
+	dataMap, queryValues := schema.GetIdentityFieldValuesMap(stmt.Context, stmt.ReflectValue, stmt.Schema.PrimaryFields)
+	if len(queryValues) == 0 {
+		return
+	}
+
+	for _, name := range stmt.Schema.PrimaryFieldDBNames {
+		columns = append(columns, name)
+	}
+
+	records := stmt.Schema.MakeSlice()
+	column, values := schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, queryValues)
+	if err := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Table(stmt.Table).Unscoped().Select(columns[0], columns[1:]...).
+		Clauses(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}}).Find(records.Interface()).Error; err != nil {
+		db.AddError(err)
+		return
+	}
+
+	for i := 0; i < records.Elem().Len(); i++ {
+		record := records.Elem().Index(i).Elem()
+		primaryValues := make([]interface{}, 0, len(stmt.Schema.PrimaryFields))
+		for _, field := range stmt.Schema.PrimaryFields {
+			value, _ := field.ValueOf(stmt.Context, record)
+			primaryValues = append(primaryValues, value)
+		}
+
+		for _, elem := range dataMap[utils.ToStringKey(primaryValues...)] {
+			for _, field := range fields {
+				value, _ := field.ValueOf(stmt.Context, record)
+				db.AddError(field.Set(stmt.Context, reflect.Indirect(elem), value))
+			}
+		}
+	}
+}
+
 type visitMap = map[reflect.Value]bool
 
 // Check if circular values, return true if loaded
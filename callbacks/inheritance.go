@@ -0,0 +1,95 @@
+package callbacks
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// inheritanceEnabled the clause name marks the statement is restricted to the records of a single table inheritance subtype
+const inheritanceEnabled = "inheritance_enabled"
+
+// addDiscriminatorCondition restricts the statement to the records of the single table inheritance subtype
+func addDiscriminatorCondition(db *gorm.DB) {
+	if db.Statement.Schema == nil || db.Statement.Schema.Inheritance == nil {
+		return
+	}
+
+	if _, ok := db.Statement.Clauses[inheritanceEnabled]; ok {
+		return
+	}
+
+	if c, ok := db.Statement.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) >= 1 {
+			for _, expr := range where.Exprs {
+				if orCond, ok := expr.(clause.OrConditions); ok && len(orCond.Exprs) == 1 {
+					where.Exprs = []clause.Expression{clause.And(where.Exprs...)}
+					c.Expression = where
+					db.Statement.Clauses["WHERE"] = c
+					break
+				}
+			}
+		}
+	}
+
+	inheritance := db.Statement.Schema.Inheritance
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{clause.Eq{
+		Column: clause.Column{Table: clause.CurrentTable, Name: inheritance.Discriminator.DBName},
+		Value:  inheritance.Value,
+	}}})
+	db.Statement.Clauses[inheritanceEnabled] = clause.Clause{}
+}
+
+// setDiscriminator fills the discriminator of the single table inheritance subtype records to create
+func setDiscriminator(db *gorm.DB) {
+	if db.Statement.Schema == nil || db.Statement.Schema.Inheritance == nil {
+		return
+	}
+
+	var (
+		inheritance = db.Statement.Schema.Inheritance
+		field       = inheritance.Discriminator
+	)
+
+	setMapValue := func(m map[string]interface{}) {
+		if _, ok := m[field.Name]; !ok {
+			if _, ok := m[field.DBName]; !ok {
+				m[field.DBName] = inheritance.Value
+			}
+		}
+	}
+
+	switch dest := db.Statement.Dest.(type) {
+	case map[string]interface{}:
+		setMapValue(dest)
+		return
+	case *map[string]interface{}:
+		if *dest != nil {
+			setMapValue(*dest)
+		}
+		return
+	case []map[string]interface{}:
+		for _, m := range dest {
+			setMapValue(m)
+		}
+		return
+	}
+
+	setValue := func(rv reflect.Value) {
+		if _, zero := field.ValueOf(db.Statement.Context, rv); zero {
+			db.AddError(field.Set(db.Statement.Context, rv, inheritance.Value))
+		}
+	}
+
+	switch db.Statement.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < db.Statement.ReflectValue.Len(); i++ {
+			if rv := reflect.Indirect(db.Statement.ReflectValue.Index(i)); rv.Kind() == reflect.Struct {
+				setValue(rv)
+			}
+		}
+	case reflect.Struct:
+		setValue(db.Statement.ReflectValue)
+	}
+}
@@ -34,6 +34,14 @@ type AfterDeleteInterface interface {
 	AfterDelete(*gorm.DB) error
 }
 
+type BeforeRestoreInterface interface {
+	BeforeRestore(*gorm.DB) error
+}
+
+type AfterRestoreInterface interface {
+	AfterRestore(*gorm.DB) error
+}
+
 type AfterFindInterface interface {
 	AfterFind(*gorm.DB) error
 }
@@ -158,6 +158,31 @@ func preloadDB(db *gorm.DB, reflectValue reflect.Value, dest interface{}) *gorm.
 }
 
 func preload(tx *gorm.DB, rel *schema.Relationship, conds []interface{}, preloads map[string][]interface{}) error {
+	if _, ok := tx.Get("gorm:preload_skip_loaded"); ok {
+		unloaded, loaded := splitLoadedValues(tx, rel, tx.Statement.ReflectValue)
+
+		// the loaded associations are skipped, but their nested associations are still preloaded
+		if len(preloads) > 0 && loaded.IsValid() && rel.FieldSchema != nil {
+			if relValues := schema.GetRelationsValues(tx.Statement.Context, loaded, []*schema.Relationship{rel}); relValues.Len() > 0 {
+				nestedTx := preloadDB(tx, relValues, relValues.Interface())
+				if err := preloadEntryPoint(nestedTx, nil, &nestedTx.Statement.Schema.Relationships, preloads, nil); err != nil {
+					return err
+				}
+			}
+		}
+
+		if tx.Statement.ReflectValue = unloaded; !tx.Statement.ReflectValue.IsValid() {
+			return nil
+		}
+	}
+
+	switch rel.Type {
+	case schema.HasManyThrough:
+		return preloadThrough(tx, rel, conds, preloads)
+	case schema.PolymorphicBelongsTo:
+		return preloadPolymorphicOwners(tx, rel, conds, preloads)
+	}
+
 	var (
 		reflectValue     = tx.Statement.ReflectValue
 		relForeignKeys   []string
@@ -166,13 +191,39 @@ func preload(tx *gorm.DB, rel *schema.Relationship, conds []interface{}, preload
 		foreignValues    [][]interface{}
 		identityMap      = map[string][]reflect.Value{}
 		inlineConds      []interface{}
+		pivotField       = rel.PivotField()
+		pivotRecords     = map[string][]reflect.Value{} // join model records of identityMap for pivot field
+		orderField       = rel.OrderByField()
+		joinRanks        map[string]int // order of join records to sort many2many associations
 	)
 
+	var limit *gorm.PreloadLimit
+	for _, cond := range conds {
+		if v, ok := cond.(gorm.PreloadLimit); ok {
+			limit = &v
+		}
+	}
+
+	// without window function, associations are limited with one query for each parent
+	if limit != nil && !supportWindowFunction(tx.Dialector) && reflectValue.Kind() != reflect.Struct && reflectValue.Len() > 1 {
+		for i := 0; i < reflectValue.Len(); i++ {
+			if parent := reflect.Indirect(reflectValue.Index(i)); parent.IsValid() {
+				parentTx := tx.Session(&gorm.Session{Context: tx.Statement.Context})
+				parentTx.Statement.ReflectValue = parent
+				if err := preload(parentTx, rel, conds, preloads); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
 	if rel.JoinTable != nil {
 		var (
 			joinForeignFields    = make([]*schema.Field, 0, len(rel.References))
 			joinRelForeignFields = make([]*schema.Field, 0, len(rel.References))
 			joinForeignKeys      = make([]string, 0, len(rel.References))
+			joinConds            = make([]clause.Expression, 0, 1)
 		)
 
 		for _, ref := range rel.References {
@@ -181,7 +232,7 @@ func preload(tx *gorm.DB, rel *schema.Relationship, conds []interface{}, preload
 				joinForeignFields = append(joinForeignFields, ref.ForeignKey)
 				foreignFields = append(foreignFields, ref.PrimaryKey)
 			} else if ref.PrimaryValue != "" {
-				tx = tx.Where(clause.Eq{Column: ref.ForeignKey.DBName, Value: ref.PrimaryValue})
+				joinConds = append(joinConds, clause.Eq{Column: ref.ForeignKey.DBName, Value: ref.PrimaryValue})
 			} else {
 				joinRelForeignFields = append(joinRelForeignFields, ref.ForeignKey)
 				relForeignKeys = append(relForeignKeys, ref.PrimaryKey.DBName)
@@ -196,7 +247,21 @@ func preload(tx *gorm.DB, rel *schema.Relationship, conds []interface{}, preload
 
 		joinResults := rel.JoinTable.MakeSlice().Elem()
 		column, values := schema.ToQueryValues(clause.CurrentTable, joinForeignKeys, joinForeignValues)
-		if err := tx.Where(clause.IN{Column: column, Values: values}).Find(joinResults.Addr().Interface()).Error; err != nil {
+		joinConds = append(joinConds, clause.IN{Column: column, Values: values})
+		joinTx := tx.Clauses(clause.Where{Exprs: joinConds})
+		if limit != nil {
+			// join records are limited for each parent, ordered by the associated records, or the position of join records
+			if orderField != nil && orderField.Schema == rel.JoinTable && limit.Order == nil {
+				limit.Order = clause.OrderByColumn{Column: clause.Column{Table: rel.JoinTable.Table, Name: orderField.DBName}}
+			}
+			joinTx = limitJoinsPerParent(tx, rel, joinForeignKeys, joinForeignValues, *limit, conds)
+			joinRanks = map[string]int{}
+		} else if orderField != nil && orderField.Schema == rel.JoinTable {
+			joinTx = joinTx.Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: orderField.DBName}})
+			joinRanks = map[string]int{}
+		}
+
+		if err := joinTx.Find(joinResults.Addr().Interface()).Error; err != nil {
 			return err
 		}
 
@@ -216,6 +281,14 @@ func preload(tx *gorm.DB, rel *schema.Relationship, conds []interface{}, preload
 			if results, ok := joinIdentityMap[utils.ToStringKey(fieldValues...)]; ok {
 				joinKey := utils.ToStringKey(joinFieldValues...)
 				identityMap[joinKey] = append(identityMap[joinKey], results...)
+				if joinRanks != nil {
+					joinRanks[utils.ToStringKey(fieldValues...)+"/"+joinKey] = i
+				}
+				if pivotField != nil {
+					for range results {
+						pivotRecords[joinKey] = append(pivotRecords[joinKey], joinIndexValue)
+					}
+				}
 			}
 		}
 
@@ -241,24 +314,33 @@ func preload(tx *gorm.DB, rel *schema.Relationship, conds []interface{}, preload
 		}
 	}
 
-	// nested preload
-	for p, pvs := range preloads {
-		tx = tx.Preload(p, pvs...)
-	}
-
 	reflectResults := rel.FieldSchema.MakeSlice().Elem()
 	column, values := schema.ToQueryValues(clause.CurrentTable, relForeignKeys, foreignValues)
 
 	if len(values) != 0 {
-		for _, cond := range conds {
-			if fc, ok := cond.(func(*gorm.DB) *gorm.DB); ok {
-				tx = fc(tx)
-			} else {
-				inlineConds = append(inlineConds, cond)
+		tx, inlineConds = applyPreloadConds(tx, conds)
+
+		if orderField != nil && orderField.Schema == rel.FieldSchema {
+			orderByColumn := clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: orderField.DBName}}
+			if limit != nil && limit.Order == nil {
+				limit.Order = orderByColumn
+			} else if _, ok := tx.Statement.Clauses["ORDER BY"]; !ok && limit == nil {
+				tx = tx.Order(orderByColumn)
 			}
 		}
 
-		if err := tx.Where(clause.IN{Column: column, Values: values}).Find(reflectResults.Addr().Interface(), inlineConds...).Error; err != nil {
+		tx = tx.Where(clause.IN{Column: column, Values: values})
+		if limit != nil && rel.JoinTable == nil {
+			tx = limitPerParent(tx, rel, relForeignKeys, *limit, inlineConds)
+			inlineConds = nil
+		}
+
+		// nested preload
+		for p, pvs := range preloads {
+			tx = tx.Preload(p, pvs...)
+		}
+
+		if err := tx.Find(reflectResults.Addr().Interface(), inlineConds...).Error; err != nil {
 			return err
 		}
 	}
@@ -291,12 +373,26 @@ func preload(tx *gorm.DB, rel *schema.Relationship, conds []interface{}, preload
 			fieldValues[idx], _ = field.ValueOf(tx.Statement.Context, elem)
 		}
 
-		datas, ok := identityMap[utils.ToStringKey(fieldValues...)]
+		key := utils.ToStringKey(fieldValues...)
+		datas, ok := identityMap[key]
 		if !ok {
 			return fmt.Errorf("failed to assign association %#v, make sure foreign fields exists", elem.Interface())
 		}
 
-		for _, data := range datas {
+		for idx, data := range datas {
+			value := elem
+			if records := pivotRecords[key]; idx < len(records) {
+				// copy elem for each owner to assign its join model record
+				pivot := records[idx]
+				if pivotField.FieldType.Kind() != reflect.Ptr {
+					pivot = pivot.Elem()
+				}
+
+				value = reflect.New(elem.Type().Elem())
+				value.Elem().Set(elem.Elem())
+				tx.AddError(pivotField.Set(tx.Statement.Context, value, pivot.Interface()))
+			}
+
 			reflectFieldValue := rel.Field.ReflectValueOf(tx.Statement.Context, data)
 			if reflectFieldValue.Kind() == reflect.Ptr && reflectFieldValue.IsNil() {
 				reflectFieldValue.Set(reflect.New(rel.Field.FieldType.Elem()))
@@ -305,12 +401,218 @@ func preload(tx *gorm.DB, rel *schema.Relationship, conds []interface{}, preload
 			reflectFieldValue = reflect.Indirect(reflectFieldValue)
 			switch reflectFieldValue.Kind() {
 			case reflect.Struct:
-				tx.AddError(rel.Field.Set(tx.Statement.Context, data, elem.Interface()))
+				tx.AddError(rel.Field.Set(tx.Statement.Context, data, value.Interface()))
 			case reflect.Slice, reflect.Array:
 				if reflectFieldValue.Type().Elem().Kind() == reflect.Ptr {
-					tx.AddError(rel.Field.Set(tx.Statement.Context, data, reflect.Append(reflectFieldValue, elem).Interface()))
+					tx.AddError(rel.Field.Set(tx.Statement.Context, data, reflect.Append(reflectFieldValue, value).Interface()))
+				} else {
+					tx.AddError(rel.Field.Set(tx.Statement.Context, data, reflect.Append(reflectFieldValue, value.Elem()).Interface()))
+				}
+			}
+		}
+	}
+
+	if joinRanks != nil {
+		sortByJoinRanks(tx, rel, reflectValue, foreignFields, relForeignFields, joinRanks)
+	}
+
+	return tx.Error
+}
+
+// preloadThrough preloads has many through relation, loads keys of the intermediate records first,
+// then preloads the relation of the intermediate records and appends the results into their owners
+func preloadThrough(tx *gorm.DB, rel *schema.Relationship, conds []interface{}, preloads map[string][]interface{}) error {
+	var (
+		through, target = rel.Through[0], rel.Through[1]
+		reflectValue    = tx.Statement.ReflectValue
+		foreignKeys     []string
+		foreignFields   []*schema.Field
+		ownerFields     []*schema.Field
+		selects         []string
+		whereConds      []clause.Expression
+	)
+
+	for _, ref := range through.References {
+		if ref.OwnPrimaryKey {
+			foreignKeys = append(foreignKeys, ref.ForeignKey.DBName)
+			foreignFields = append(foreignFields, ref.ForeignKey)
+			ownerFields = append(ownerFields, ref.PrimaryKey)
+			selects = append(selects, ref.ForeignKey.DBName)
+		} else if ref.PrimaryValue != "" {
+			whereConds = append(whereConds, clause.Eq{Column: ref.ForeignKey.DBName, Value: ref.PrimaryValue})
+		}
+	}
+
+	for _, ref := range target.References {
+		if ref.OwnPrimaryKey {
+			selects = append(selects, ref.PrimaryKey.DBName)
+		}
+	}
+
+	identityMap, foreignValues := schema.GetIdentityFieldValuesMap(tx.Statement.Context, reflectValue, ownerFields)
+	if len(foreignValues) == 0 {
+		return nil
+	}
+
+	intermediates := through.FieldSchema.MakeSlice().Elem()
+	column, values := schema.ToQueryValues(clause.CurrentTable, foreignKeys, foreignValues)
+	whereConds = append(whereConds, clause.IN{Column: column, Values: values})
+	if err := tx.Select(selects).Clauses(clause.Where{Exprs: whereConds}).Find(intermediates.Addr().Interface()).Error; err != nil {
+		return err
+	}
+
+	targetTx := tx.Table("").Session(&gorm.Session{Context: tx.Statement.Context, SkipHooks: tx.Statement.SkipHooks})
+	targetTx.Statement.ReflectValue = intermediates
+	targetTx.Statement.Unscoped = tx.Statement.Unscoped
+	if err := preload(targetTx, target, conds, preloads); err != nil {
+		return err
+	}
+
+	// clean up old values before preloading
+	switch reflectValue.Kind() {
+	case reflect.Struct:
+		tx.AddError(rel.Field.Set(tx.Statement.Context, reflectValue, reflect.MakeSlice(rel.Field.IndirectFieldType, 0, 10).Interface()))
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < reflectValue.Len(); i++ {
+			tx.AddError(rel.Field.Set(tx.Statement.Context, reflectValue.Index(i), reflect.MakeSlice(rel.Field.IndirectFieldType, 0, 10).Interface()))
+		}
+	}
+
+	isPtr := rel.Field.IndirectFieldType.Elem().Kind() == reflect.Ptr
+	fieldValues := make([]interface{}, len(foreignFields))
+	for i := 0; i < intermediates.Len(); i++ {
+		elem := intermediates.Index(i)
+		for idx, field := range foreignFields {
+			fieldValues[idx], _ = field.ValueOf(tx.Statement.Context, elem)
+		}
+
+		var results []reflect.Value
+		if targetValue, isZero := target.Field.ValueOf(tx.Statement.Context, elem); isZero || targetValue == nil {
+			continue
+		} else if value := reflect.Indirect(target.Field.ReflectValueOf(tx.Statement.Context, elem)); value.Kind() == reflect.Slice {
+			for j := 0; j < value.Len(); j++ {
+				results = append(results, value.Index(j))
+			}
+		} else {
+			results = append(results, value)
+		}
+
+		for _, data := range identityMap[utils.ToStringKey(fieldValues...)] {
+			reflectFieldValue := reflect.Indirect(rel.Field.ReflectValueOf(tx.Statement.Context, data))
+			for _, result := range results {
+				if result.Kind() == reflect.Ptr && !isPtr {
+					result = result.Elem()
+				} else if result.Kind() != reflect.Ptr && isPtr {
+					result = result.Addr()
+				}
+				reflectFieldValue = reflect.Append(reflectFieldValue, result)
+			}
+			tx.AddError(rel.Field.Set(tx.Statement.Context, data, reflectFieldValue.Interface()))
+		}
+	}
+
+	return tx.Error
+}
+
+// preloadPolymorphicOwners preloads the owners of polymorphic belongs to relation, records are grouped by polymorphic type,
+// owners of each type are queried with their registered model
+func preloadPolymorphicOwners(tx *gorm.DB, rel *schema.Relationship, conds []interface{}, preloads map[string][]interface{}) error {
+	var (
+		ctx          = tx.Statement.Context
+		reflectValue = tx.Statement.ReflectValue
+		typeValues   []string
+		identityMaps = map[string]map[string][]reflect.Value{} // type value => owner id => records
+		idValues     = map[string][]interface{}{}
+	)
+
+	collect := func(rv reflect.Value) {
+		// clean up old values before preloading
+		fieldValue := rel.Field.ReflectValueOf(ctx, rv)
+		fieldValue.Set(reflect.Zero(fieldValue.Type()))
+
+		typeValue, zero := rel.Polymorphic.PolymorphicType.ValueOf(ctx, rv)
+		if zero {
+			return
+		}
+
+		idValue, zero := rel.Polymorphic.PolymorphicID.ValueOf(ctx, rv)
+		if zero {
+			return
+		}
+
+		typeKey, idKey := fmt.Sprint(reflect.Indirect(reflect.ValueOf(typeValue)).Interface()), utils.ToStringKey(idValue)
+		identityMap, ok := identityMaps[typeKey]
+		if !ok {
+			identityMap = map[string][]reflect.Value{}
+			identityMaps[typeKey] = identityMap
+			typeValues = append(typeValues, typeKey)
+		}
+
+		if _, ok := identityMap[idKey]; !ok {
+			idValues[typeKey] = append(idValues[typeKey], idValue)
+		}
+		identityMap[idKey] = append(identityMap[idKey], fieldValue)
+	}
+
+	switch reflectValue.Kind() {
+	case reflect.Struct:
+		collect(reflectValue)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < reflectValue.Len(); i++ {
+			if elem := reflect.Indirect(reflectValue.Index(i)); elem.Kind() == reflect.Struct {
+				collect(elem)
+			}
+		}
+	}
+
+	for _, typeValue := range typeValues {
+		owner := rel.LookUpPolymorphicType(typeValue)
+		if owner == nil {
+			return fmt.Errorf("%s: %w, no model registered for polymorphic type %s, register it with SetupPolymorphicType", rel.Name, gorm.ErrUnsupportedRelation, typeValue)
+		}
+
+		primaryField := owner.PrioritizedPrimaryField
+		if primaryField == nil {
+			return fmt.Errorf("%s: %w, missing primary key of %s", rel.Name, gorm.ErrPrimaryKeyRequired, owner.Name)
+		}
+
+		var (
+			ownerTx     = tx
+			inlineConds []interface{}
+			results     = owner.MakeSlice().Elem()
+		)
+
+		for _, cond := range conds {
+			switch v := cond.(type) {
+			case func(*gorm.DB) *gorm.DB:
+				ownerTx = v(ownerTx)
+			case gorm.PreloadLimit:
+				return fmt.Errorf("%s: %w with per parent limit", rel.Name, gorm.ErrUnsupportedRelation)
+			default:
+				inlineConds = append(inlineConds, cond)
+			}
+		}
+
+		// nested preload
+		for p, pvs := range preloads {
+			ownerTx = ownerTx.Preload(p, pvs...)
+		}
+
+		ownerTx = ownerTx.Where(clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: primaryField.DBName}, Values: idValues[typeValue]})
+		if err := ownerTx.Find(results.Addr().Interface(), inlineConds...).Error; err != nil {
+			return err
+		}
+
+		for i := 0; i < results.Len(); i++ {
+			result := results.Index(i)
+			id, _ := primaryField.ValueOf(ctx, result)
+			for _, fieldValue := range identityMaps[typeValue][utils.ToStringKey(id)] {
+				if result.Type().AssignableTo(fieldValue.Type()) {
+					fieldValue.Set(result)
+				} else if result.Elem().Type().AssignableTo(fieldValue.Type()) {
+					fieldValue.Set(result.Elem())
 				} else {
-					tx.AddError(rel.Field.Set(tx.Statement.Context, data, reflect.Append(reflectFieldValue, elem.Elem()).Interface()))
+					return fmt.Errorf("%s: %w, %v doesn't implement %v", rel.Name, gorm.ErrInvalidData, result.Type(), fieldValue.Type())
 				}
 			}
 		}
@@ -318,3 +620,228 @@ func preload(tx *gorm.DB, rel *schema.Relationship, conds []interface{}, preload
 
 	return tx.Error
 }
+
+// sortByJoinRanks sorts the preloaded many2many associations of each record by the order of their join records
+func sortByJoinRanks(tx *gorm.DB, rel *schema.Relationship, reflectValue reflect.Value, foreignFields, relForeignFields []*schema.Field, ranks map[string]int) {
+	keyOf := func(fields []*schema.Field, rv reflect.Value) string {
+		values := make([]interface{}, len(fields))
+		for idx, field := range fields {
+			values[idx], _ = field.ValueOf(tx.Statement.Context, rv)
+		}
+		return utils.ToStringKey(values...)
+	}
+
+	sortRecord := func(data reflect.Value) {
+		fieldValue := reflect.Indirect(rel.Field.ReflectValueOf(tx.Statement.Context, data))
+		if fieldValue.Kind() != reflect.Slice || fieldValue.Len() < 2 {
+			return
+		}
+
+		ownerKey := keyOf(foreignFields, data)
+		rankOf := func(i int) int {
+			return ranks[ownerKey+"/"+keyOf(relForeignFields, fieldValue.Index(i))]
+		}
+
+		sort.SliceStable(fieldValue.Interface(), func(i, j int) bool {
+			return rankOf(i) < rankOf(j)
+		})
+	}
+
+	switch reflectValue.Kind() {
+	case reflect.Struct:
+		sortRecord(reflectValue)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < reflectValue.Len(); i++ {
+			if data := reflect.Indirect(reflectValue.Index(i)); data.Kind() == reflect.Struct {
+				sortRecord(data)
+			}
+		}
+	}
+}
+
+// splitLoadedValues splits records into the ones whose association rel is not loaded yet and the loaded ones,
+// returns invalid values if none of them
+func splitLoadedValues(tx *gorm.DB, rel *schema.Relationship, reflectValue reflect.Value) (unloaded, loaded reflect.Value) {
+	switch reflectValue.Kind() {
+	case reflect.Struct:
+		if _, isZero := rel.Field.ValueOf(tx.Statement.Context, reflectValue); isZero {
+			return reflectValue, loaded
+		}
+		return unloaded, reflectValue
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < reflectValue.Len(); i++ {
+			elem := reflectValue.Index(i)
+			if reflect.Indirect(elem).Kind() != reflect.Struct {
+				continue
+			}
+
+			if elem.Kind() != reflect.Ptr {
+				elem = elem.Addr()
+			}
+
+			if _, isZero := rel.Field.ValueOf(tx.Statement.Context, elem); isZero {
+				if !unloaded.IsValid() {
+					unloaded = reflect.MakeSlice(reflect.SliceOf(elem.Type()), 0, reflectValue.Len()-i)
+				}
+				unloaded = reflect.Append(unloaded, elem)
+			} else {
+				if !loaded.IsValid() {
+					loaded = reflect.MakeSlice(reflect.SliceOf(elem.Type()), 0, reflectValue.Len()-i)
+				}
+				loaded = reflect.Append(loaded, elem)
+			}
+		}
+	}
+	return unloaded, loaded
+}
+
+// applyPreloadConds applies the scopes of preload conditions to tx, returns tx and the inline conditions
+func applyPreloadConds(tx *gorm.DB, conds []interface{}) (*gorm.DB, []interface{}) {
+	var inlineConds []interface{}
+	for _, cond := range conds {
+		switch v := cond.(type) {
+		case func(*gorm.DB) *gorm.DB:
+			tx = v(tx)
+		case gorm.PreloadLimit:
+		default:
+			inlineConds = append(inlineConds, cond)
+		}
+	}
+	return tx, inlineConds
+}
+
+// limitPerParent numbers records of the preload query for each parent with window function ROW_NUMBER in a subquery,
+// returns a query selecting the first limit.Limit records of each parent from it, or limits the query of single parent
+// if window function is unsupported
+func limitPerParent(tx *gorm.DB, rel *schema.Relationship, foreignKeys []string, limit gorm.PreloadLimit, conds []interface{}) *gorm.DB {
+	partitions := make([]clause.Column, 0, len(foreignKeys))
+	for _, key := range foreignKeys {
+		partitions = append(partitions, clause.Column{Table: clause.CurrentTable, Name: key})
+	}
+
+	if len(conds) > 0 {
+		tx = tx.Where(conds[0], conds[1:]...)
+	}
+
+	rowNumber := newRowNumberExpr(rel, partitions, limit)
+	if !supportWindowFunction(tx.Dialector) {
+		return tx.Clauses(rowNumber.OrderBy).Limit(limit.Limit)
+	}
+
+	modelValue := reflect.New(rel.FieldSchema.ModelType).Interface()
+	subQuery := tx.Model(modelValue).Select("?.*, ?", clause.Table{Name: clause.CurrentTable}, rowNumber)
+
+	queryTx := preloadDB(tx, reflect.Value{}, modelValue)
+	return queryTx.Table("(?) AS ?", subQuery, clause.Table{Name: rel.FieldSchema.Table}).
+		Where(clause.Lte{Column: rowNumberColumn, Value: limit.Limit}).
+		Order(clause.OrderByColumn{Column: rowNumberColumn})
+}
+
+// limitJoinsPerParent numbers join records of many2many relation for each parent with window function ROW_NUMBER,
+// ordered by the associated records joined in a subquery, returns a query selecting the first limit.Limit join records
+// of each parent from it, or limits the join records of single parent if window function is unsupported, e.g:
+//
+//	SELECT * FROM (SELECT user_languages.*, ROW_NUMBER() OVER (PARTITION BY user_languages.user_id ORDER BY languages.code) AS gorm_row_number
+//	FROM languages INNER JOIN user_languages ON user_languages.language_code = languages.code WHERE user_languages.user_id IN (...)) AS user_languages
+//	WHERE gorm_row_number <= 5 ORDER BY gorm_row_number
+func limitJoinsPerParent(tx *gorm.DB, rel *schema.Relationship, joinForeignKeys []string, joinForeignValues [][]interface{}, limit gorm.PreloadLimit, conds []interface{}) *gorm.DB {
+	var (
+		joinTable  = clause.Table{Name: rel.JoinTable.Table}
+		partitions = make([]clause.Column, 0, len(joinForeignKeys))
+		onExprs    = make([]clause.Expression, 0, len(rel.References))
+		whereExprs = make([]clause.Expression, 0, len(rel.References))
+	)
+
+	for _, key := range joinForeignKeys {
+		partitions = append(partitions, clause.Column{Table: joinTable.Name, Name: key})
+	}
+
+	for _, ref := range rel.References {
+		if ref.PrimaryValue != "" {
+			whereExprs = append(whereExprs, clause.Eq{Column: clause.Column{Table: joinTable.Name, Name: ref.ForeignKey.DBName}, Value: ref.PrimaryValue})
+		} else if !ref.OwnPrimaryKey {
+			onExprs = append(onExprs, clause.Eq{
+				Column: clause.Column{Table: joinTable.Name, Name: ref.ForeignKey.DBName},
+				Value:  clause.Column{Table: clause.CurrentTable, Name: ref.PrimaryKey.DBName},
+			})
+		}
+	}
+	onExprs = append(onExprs, joinQueryClausesExprs(tx, joinTable.Name, rel.JoinTable, nil)...)
+
+	column, values := schema.ToQueryValues(joinTable.Name, joinForeignKeys, joinForeignValues)
+	whereExprs = append(whereExprs, clause.IN{Column: column, Values: values})
+
+	modelValue := reflect.New(rel.FieldSchema.ModelType).Interface()
+	subQueryTx, inlineConds := applyPreloadConds(tx.Model(modelValue), conds)
+	subQuery := subQueryTx.Joins("INNER JOIN ? ON ?", joinTable, clause.And(onExprs...)).Where(clause.And(whereExprs...))
+	if len(inlineConds) > 0 {
+		subQuery = subQuery.Where(inlineConds[0], inlineConds[1:]...)
+	}
+
+	rowNumber := newRowNumberExpr(rel, partitions, limit)
+	if !supportWindowFunction(tx.Dialector) {
+		return subQuery.Select("?.*", joinTable).Clauses(rowNumber.OrderBy).Limit(limit.Limit)
+	}
+	subQuery = subQuery.Select("?.*, ?", joinTable, rowNumber)
+
+	queryTx := preloadDB(tx, reflect.Value{}, reflect.New(rel.JoinTable.ModelType).Interface())
+	return queryTx.Table("(?) AS ?", subQuery, joinTable).
+		Where(clause.Lte{Column: rowNumberColumn, Value: limit.Limit}).
+		Order(clause.OrderByColumn{Column: rowNumberColumn})
+}
+
+// newRowNumberExpr numbers records of rel in each partition, ordered by limit.Order, or the primary keys of rel
+func newRowNumberExpr(rel *schema.Relationship, partitions []clause.Column, limit gorm.PreloadLimit) rowNumberExpr {
+	rowNumber := rowNumberExpr{Partitions: partitions}
+	switch v := limit.Order.(type) {
+	case string:
+		if v != "" {
+			rowNumber.OrderBy.Columns = append(rowNumber.OrderBy.Columns, clause.OrderByColumn{Column: clause.Column{Name: v, Raw: true}})
+		}
+	case clause.OrderByColumn:
+		rowNumber.OrderBy.Columns = append(rowNumber.OrderBy.Columns, v)
+	case clause.OrderBy:
+		rowNumber.OrderBy = v
+	}
+
+	if len(rowNumber.OrderBy.Columns) == 0 && rowNumber.OrderBy.Expression == nil {
+		for _, field := range rel.FieldSchema.PrimaryFields {
+			rowNumber.OrderBy.Columns = append(rowNumber.OrderBy.Columns, clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}})
+		}
+
+		if len(rowNumber.OrderBy.Columns) == 0 {
+			rowNumber.OrderBy.Columns = append(rowNumber.OrderBy.Columns, clause.OrderByColumn{Column: rowNumber.Partitions[0]})
+		}
+	}
+	return rowNumber
+}
+
+var rowNumberColumn = clause.Column{Name: "gorm_row_number"}
+
+// supportWindowFunction window function is supported only if the dialector reports it with WindowFunctionDialectorInterface
+func supportWindowFunction(dialector gorm.Dialector) bool {
+	if d, ok := dialector.(gorm.WindowFunctionDialectorInterface); ok {
+		return d.SupportWindowFunction()
+	}
+	return false
+}
+
+// rowNumberExpr numbers rows in each partition, e.g: ROW_NUMBER() OVER (PARTITION BY `post_id` ORDER BY `created_at` DESC)
+type rowNumberExpr struct {
+	Partitions []clause.Column
+	OrderBy    clause.OrderBy
+}
+
+func (expr rowNumberExpr) Build(builder clause.Builder) {
+	builder.WriteString("ROW_NUMBER() OVER (PARTITION BY ")
+	for idx, column := range expr.Partitions {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteQuoted(column)
+	}
+	builder.WriteString(" ORDER BY ")
+	expr.OrderBy.Build(builder)
+	builder.WriteString(") AS ")
+	builder.WriteQuoted(rowNumberColumn)
+}
@@ -31,6 +31,7 @@ func Query(db *gorm.DB) {
 
 func BuildQuerySQL(db *gorm.DB) {
 	if db.Statement.Schema != nil {
+		addDiscriminatorCondition(db)
 		for _, c := range db.Statement.Schema.QueryClauses {
 			db.Statement.AddClause(c)
 		}
@@ -110,13 +111,19 @@ func BuildQuerySQL(db *gorm.DB) {
 				}
 			}
 
-			specifiedRelationsName := make(map[string]interface{})
+			var (
+				specifiedRelationsName = make(map[string]interface{})
+				joinsCollection        bool
+			)
 			for _, join := range db.Statement.Joins {
 				if db.Statement.Schema != nil {
 					var isRelations bool // is relations or raw sql
 					var relations []*schema.Relationship
 					relation, ok := db.Statement.Schema.Relationships.Relations[join.Name]
-					if ok {
+					if ok && relation.Type == schema.PolymorphicBelongsTo {
+						db.AddError(fmt.Errorf("%s: %w for joins", join.Name, gorm.ErrUnsupportedRelation))
+						return
+					} else if ok {
 						isRelations = true
 						relations = append(relations, relation)
 					} else {
@@ -128,7 +135,7 @@ func BuildQuerySQL(db *gorm.DB) {
 							currentRelations := db.Statement.Schema.Relationships.Relations
 							for _, relname := range nestedJoinNames {
 								// incomplete match, only treated as raw sql
-								if relation, ok = currentRelations[relname]; ok {
+								if relation, ok = currentRelations[relname]; ok && relation.FieldSchema != nil {
 									gussNestedRelations = append(gussNestedRelations, relation)
 									currentRelations = relation.FieldSchema.Relationships.Relations
 								} else {
@@ -145,7 +152,7 @@ func BuildQuerySQL(db *gorm.DB) {
 					}
 
 					if isRelations {
-						genJoinClause := func(joinType clause.JoinType, parentTableName string, relation *schema.Relationship) clause.Join {
+						genJoinClauses := func(joinType clause.JoinType, parentTableName string, relation *schema.Relationship) []clause.Join {
 							tableAliasName := relation.Name
 							if parentTableName != clause.CurrentTable {
 								tableAliasName = utils.NestedRelationName(parentTableName, tableAliasName)
@@ -167,70 +174,78 @@ func BuildQuerySQL(db *gorm.DB) {
 								}
 							}
 
-							exprs := make([]clause.Expression, len(relation.References))
-							for idx, ref := range relation.References {
-								if ref.OwnPrimaryKey {
-									exprs[idx] = clause.Eq{
-										Column: clause.Column{Table: parentTableName, Name: ref.PrimaryKey.DBName},
-										Value:  clause.Column{Table: tableAliasName, Name: ref.ForeignKey.DBName},
-									}
-								} else {
-									if ref.PrimaryValue == "" {
-										exprs[idx] = clause.Eq{
-											Column: clause.Column{Table: parentTableName, Name: ref.ForeignKey.DBName},
-											Value:  clause.Column{Table: tableAliasName, Name: ref.PrimaryKey.DBName},
-										}
-									} else {
-										exprs[idx] = clause.Eq{
-											Column: clause.Column{Table: tableAliasName, Name: ref.ForeignKey.DBName},
+							var (
+								joinClauses = make([]clause.Join, 0, 2)
+								exprs       = make([]clause.Expression, 0, len(relation.References))
+							)
+
+							if relation.JoinTable != nil {
+								// many2many relations join the join table first, e.g: `Languages__UserSpeak`
+								joinTableAliasName := utils.NestedRelationName(tableAliasName, relation.JoinTable.Name)
+								joinTableExprs := make([]clause.Expression, 0, len(relation.References))
+								for _, ref := range relation.References {
+									if ref.OwnPrimaryKey {
+										joinTableExprs = append(joinTableExprs, clause.Eq{
+											Column: clause.Column{Table: parentTableName, Name: ref.PrimaryKey.DBName},
+											Value:  clause.Column{Table: joinTableAliasName, Name: ref.ForeignKey.DBName},
+										})
+									} else if ref.PrimaryValue != "" {
+										joinTableExprs = append(joinTableExprs, clause.Eq{
+											Column: clause.Column{Table: joinTableAliasName, Name: ref.ForeignKey.DBName},
 											Value:  ref.PrimaryValue,
-										}
+										})
+									} else {
+										exprs = append(exprs, clause.Eq{
+											Column: clause.Column{Table: joinTableAliasName, Name: ref.ForeignKey.DBName},
+											Value:  clause.Column{Table: tableAliasName, Name: ref.PrimaryKey.DBName},
+										})
 									}
 								}
-							}
-
-							{
-								onStmt := gorm.Statement{Table: tableAliasName, DB: db, Clauses: map[string]clause.Clause{}}
-								for _, c := range relation.FieldSchema.QueryClauses {
-									onStmt.AddClause(c)
-								}
-
-								if join.On != nil {
-									onStmt.AddClause(join.On)
-								}
 
-								if cs, ok := onStmt.Clauses["WHERE"]; ok {
-									if where, ok := cs.Expression.(clause.Where); ok {
-										where.Build(&onStmt)
-
-										if onSQL := onStmt.SQL.String(); onSQL != "" {
-											vars := onStmt.Vars
-											for idx, v := range vars {
-												bindvar := strings.Builder{}
-												onStmt.Vars = vars[0 : idx+1]
-												db.Dialector.BindVarTo(&bindvar, &onStmt, v)
-												onSQL = strings.Replace(onSQL, bindvar.String(), "?", 1)
-											}
-
-											exprs = append(exprs, clause.Expr{SQL: onSQL, Vars: vars})
-										}
-									}
-								}
+								// excludes soft deleted join records
+								joinTableExprs = append(joinTableExprs, joinQueryClausesExprs(db, joinTableAliasName, relation.JoinTable, nil)...)
+
+								joinClauses = append(joinClauses, clause.Join{
+									Type:  joinType,
+									Table: clause.Table{Name: relation.JoinTable.Table, Alias: joinTableAliasName},
+									ON:    clause.Where{Exprs: joinTableExprs},
+								})
+							} else if len(relation.Through) > 0 {
+								// has many through relations join the intermediate table first, e.g: `Posts__Users`
+								through := relation.Through[0]
+								throughAliasName := utils.NestedRelationName(tableAliasName, through.Name)
+								throughExprs := append(joinRelationExprs(through, parentTableName, throughAliasName),
+									joinQueryClausesExprs(db, throughAliasName, through.FieldSchema, nil)...)
+
+								joinClauses = append(joinClauses, clause.Join{
+									Type:  joinType,
+									Table: clause.Table{Name: through.FieldSchema.Table, Alias: throughAliasName},
+									ON:    clause.Where{Exprs: throughExprs},
+								})
+								exprs = joinRelationExprs(relation.Through[1], throughAliasName, tableAliasName)
+							} else {
+								exprs = joinRelationExprs(relation, parentTableName, tableAliasName)
 							}
 
-							return clause.Join{
+							exprs = append(exprs, joinQueryClausesExprs(db, tableAliasName, relation.FieldSchema, join.On)...)
+
+							return append(joinClauses, clause.Join{
 								Type:  joinType,
 								Table: clause.Table{Name: relation.FieldSchema.Table, Alias: tableAliasName},
 								ON:    clause.Where{Exprs: exprs},
-							}
+							})
 						}
 
 						parentTableName := clause.CurrentTable
 						for _, rel := range relations {
+							if rel.Field.IndirectFieldType.Kind() == reflect.Slice {
+								joinsCollection = true
+							}
+
 							// joins table alias like "Manager, Company, Manager__Company"
 							nestedAlias := utils.NestedRelationName(parentTableName, rel.Name)
 							if _, ok := specifiedRelationsName[nestedAlias]; !ok {
-								fromClause.Joins = append(fromClause.Joins, genJoinClause(join.JoinType, parentTableName, rel))
+								fromClause.Joins = append(fromClause.Joins, genJoinClauses(join.JoinType, parentTableName, rel)...)
 								specifiedRelationsName[nestedAlias] = nil
 							}
 
@@ -253,10 +268,17 @@ func BuildQuerySQL(db *gorm.DB) {
 			}
 
 			db.Statement.AddClause(fromClause)
+			if joinsCollection {
+				limitJoinedParents(db)
+			}
 		} else {
 			db.Statement.AddClauseIfNotExists(clause.From{})
 		}
 
+		if len(db.Statement.Aggregates) > 0 {
+			buildAggregates(db, clauseSelect)
+		}
+
 		db.Statement.AddClauseIfNotExists(clauseSelect)
 
 		db.Statement.Build(db.Statement.BuildClauses...)
@@ -287,6 +309,10 @@ func Preload(db *gorm.DB) {
 func AfterQuery(db *gorm.DB) {
 	// clear the joins after query because preload need it
 	db.Statement.Joins = nil
+	if db.Error == nil && db.Statement.Schema != nil && db.RowsAffected > 0 {
+		db.Statement.TakeSnapshot()
+	}
+
 	if db.Error == nil && db.Statement.Schema != nil && !db.Statement.SkipHooks && db.Statement.Schema.AfterFind && db.RowsAffected > 0 {
 		callMethod(db, func(value interface{}, tx *gorm.DB) bool {
 			if i, ok := value.(AfterFindInterface); ok {
@@ -297,3 +323,114 @@ func AfterQuery(db *gorm.DB) {
 		})
 	}
 }
+
+// limitJoinedParents applies limit, offset to the parent records instead of the joined rows when joining has many,
+// many2many relations, the primary keys of parent records are limited in a subquery with the same joins, conditions
+// and orders, grouped by the primary keys, e.g:
+//
+//	SELECT ... FROM users LEFT JOIN pets Pets ON ... WHERE users.id IN (SELECT * FROM (SELECT users.id FROM users LEFT JOIN pets Pets ON ... WHERE Pets.name = ... GROUP BY users.id ORDER BY users.id LIMIT 1) AS gorm_limited)
+func limitJoinedParents(db *gorm.DB) {
+	c, ok := db.Statement.Clauses["LIMIT"]
+	if !ok {
+		return
+	}
+
+	if limit, ok := c.Expression.(clause.Limit); !ok || ((limit.Limit == nil || *limit.Limit < 0) && limit.Offset <= 0) {
+		return
+	}
+
+	if len(db.Statement.Schema.PrimaryFields) == 0 {
+		db.AddError(fmt.Errorf("%w when limiting records with joined has many or many2many relations", gorm.ErrPrimaryKeyRequired))
+		return
+	}
+
+	var (
+		columns  = make([]interface{}, 0, len(db.Statement.Schema.PrimaryFields))
+		selects  = make([]clause.Column, 0, len(db.Statement.Schema.PrimaryFields))
+		subQuery = db.Session(&gorm.Session{NewDB: true}).Unscoped().Table(db.Statement.Table)
+	)
+
+	// query clauses are copied from current statement, the schema resolves primary key columns of orders
+	subQuery.Statement.Schema = db.Statement.Schema
+
+	for _, field := range db.Statement.Schema.PrimaryFields {
+		column := clause.Column{Table: db.Statement.Table, Name: field.DBName}
+		columns = append(columns, column)
+		selects = append(selects, column)
+	}
+
+	subQuery.Statement.AddClause(clause.Select{Columns: selects})
+	subQuery.Statement.AddClause(clause.GroupBy{Columns: selects})
+	for _, name := range []string{"FROM", "WHERE", "ORDER BY", "LIMIT"} {
+		if c, ok := db.Statement.Clauses[name]; ok {
+			subQuery.Statement.Clauses[name] = c
+		}
+	}
+
+	delete(db.Statement.Clauses, "LIMIT")
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Expr{SQL: "? IN (SELECT * FROM (?) AS gorm_limited)", Vars: []interface{}{columns, subQuery}},
+	}})
+}
+
+// joinRelationExprs builds join conditions of has one, has many and belongs to relation
+func joinRelationExprs(relation *schema.Relationship, parentTableName, tableAliasName string) []clause.Expression {
+	exprs := make([]clause.Expression, 0, len(relation.References))
+	for _, ref := range relation.References {
+		if ref.OwnPrimaryKey {
+			exprs = append(exprs, clause.Eq{
+				Column: clause.Column{Table: parentTableName, Name: ref.PrimaryKey.DBName},
+				Value:  clause.Column{Table: tableAliasName, Name: ref.ForeignKey.DBName},
+			})
+		} else if ref.PrimaryValue == "" {
+			exprs = append(exprs, clause.Eq{
+				Column: clause.Column{Table: parentTableName, Name: ref.ForeignKey.DBName},
+				Value:  clause.Column{Table: tableAliasName, Name: ref.PrimaryKey.DBName},
+			})
+		} else {
+			exprs = append(exprs, clause.Eq{
+				Column: clause.Column{Table: tableAliasName, Name: ref.ForeignKey.DBName},
+				Value:  ref.PrimaryValue,
+			})
+		}
+	}
+	return exprs
+}
+
+// joinQueryClausesExprs builds query clauses of the joined schema (e.g: soft delete) and the On conditions of join
+func joinQueryClausesExprs(db *gorm.DB, tableAliasName string, s *schema.Schema, on *clause.Where) []clause.Expression {
+	onStmt := gorm.Statement{Table: tableAliasName, DB: db, Clauses: map[string]clause.Clause{}}
+	if s.Inheritance != nil {
+		onStmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.Eq{
+			Column: clause.Column{Table: clause.CurrentTable, Name: s.Inheritance.Discriminator.DBName},
+			Value:  s.Inheritance.Value,
+		}}})
+	}
+
+	for _, c := range s.QueryClauses {
+		onStmt.AddClause(c)
+	}
+
+	if on != nil {
+		onStmt.AddClause(on)
+	}
+
+	if cs, ok := onStmt.Clauses["WHERE"]; ok {
+		if where, ok := cs.Expression.(clause.Where); ok {
+			where.Build(&onStmt)
+
+			if onSQL := onStmt.SQL.String(); onSQL != "" {
+				vars := onStmt.Vars
+				for idx, v := range vars {
+					bindvar := strings.Builder{}
+					onStmt.Vars = vars[0 : idx+1]
+					db.Dialector.BindVarTo(&bindvar, &onStmt, v)
+					onSQL = strings.Replace(onSQL, bindvar.String(), "?", 1)
+				}
+
+				return []clause.Expression{clause.Expr{SQL: onSQL, Vars: vars}}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package callbacks
+
+import (
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+func BeforeRestore(db *gorm.DB) {
+	if db.Error == nil && db.Statement.Schema != nil && !db.Statement.SkipHooks && db.Statement.Schema.BeforeRestore {
+		callMethod(db, func(value interface{}, tx *gorm.DB) bool {
+			if i, ok := value.(BeforeRestoreInterface); ok {
+				db.AddError(i.BeforeRestore(tx))
+				return true
+			}
+
+			return false
+		})
+	}
+}
+
+// RestoreBeforeAssociations restores selected has one, has many associations and relations with `onSoftDelete:cascade`,
+// which were soft deleted at the same time as their owner
+func RestoreBeforeAssociations(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	var (
+		selectColumns, restricted = db.Statement.SelectAndOmitColumns(true, false)
+		rels                      []*schema.Relationship
+	)
+
+	for _, rel := range db.Statement.Schema.Relationships.Relations {
+		if v, ok := selectColumns[rel.Name]; ok && v && restricted && (rel.Type == schema.HasOne || rel.Type == schema.HasMany) {
+			rels = append(rels, rel)
+		} else if !ok && rel.Schema == db.Statement.Schema && softDeleteCascade(rel) {
+			rels = append(rels, rel)
+		}
+	}
+
+	restoreClause, ok := findRestoreClause(db.Statement.Schema)
+	if len(rels) == 0 || !ok {
+		return
+	}
+
+	owners, ok := findStatementRecords(db, true, restoreClause.DeletedAtCondition(time.Time{}))
+	if !ok || owners.Len() == 0 {
+		return
+	}
+
+	// group owners by deletion time, associations are restored only if deleted together with their owner
+	var (
+		deletedTimes []time.Time
+		groups       = map[int64]reflect.Value{}
+	)
+	for i := 0; i < owners.Len(); i++ {
+		deletedAt, _ := restoreClause.DeletedAt(db.Statement.Context, owners.Index(i))
+		key := deletedAt.UnixNano()
+		if _, ok := groups[key]; !ok {
+			deletedTimes = append(deletedTimes, deletedAt)
+			groups[key] = reflect.MakeSlice(owners.Type(), 0, 1)
+		}
+		groups[key] = reflect.Append(groups[key], owners.Index(i))
+	}
+
+	for _, rel := range rels {
+		for _, deletedAt := range deletedTimes {
+			var (
+				modelValue interface{}
+				queryConds []clause.Expression
+				tx         = db.Session(&gorm.Session{NewDB: true})
+			)
+
+			switch rel.Type {
+			case schema.HasOne, schema.HasMany:
+				relRestoreClause, ok := findRestoreClause(rel.FieldSchema)
+				if !ok {
+					continue
+				}
+
+				modelValue = reflect.New(rel.FieldSchema.ModelType).Interface()
+				queryConds = append(rel.ToQueryConditions(db.Statement.Context, groups[deletedAt.UnixNano()]), relRestoreClause.DeletedAtCondition(deletedAt))
+				tx = tx.Model(modelValue)
+				if selects := associationSelects(db, rel.Name); len(selects) > 0 {
+					tx = tx.Select(selects)
+				}
+			case schema.Many2Many:
+				relRestoreClause, ok := findRestoreClause(rel.JoinTable)
+				if !ok {
+					continue
+				}
+
+				modelValue = reflect.New(rel.JoinTable.ModelType).Interface()
+				queryConds = append(joinTableConditions(db, rel, groups[deletedAt.UnixNano()]), relRestoreClause.DeletedAtCondition(deletedAt))
+				tx = tx.Model(modelValue).Table(rel.JoinTable.Table)
+			default:
+				continue
+			}
+
+			if db.AddError(tx.Clauses(clause.Where{Exprs: queryConds}).Restore(modelValue).Error) != nil {
+				return
+			}
+		}
+	}
+}
+
+func Restore(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+
+	if db.Statement.Schema == nil || len(db.Statement.Schema.RestoreClauses) == 0 {
+		db.AddError(gorm.ErrSoftDeleteRequired)
+		return
+	}
+
+	for _, c := range db.Statement.Schema.RestoreClauses {
+		db.Statement.AddClause(c)
+	}
+
+	checkMissingWhereConditions(db)
+
+	if !db.DryRun && db.Error == nil {
+		result, err := db.Statement.ConnPool.ExecContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
+		if db.AddError(err) == nil {
+			db.RowsAffected, _ = result.RowsAffected()
+		}
+	}
+}
+
+func AfterRestore(db *gorm.DB) {
+	if db.Error == nil && db.Statement.Schema != nil && !db.Statement.SkipHooks && db.Statement.Schema.AfterRestore {
+		callMethod(db, func(value interface{}, tx *gorm.DB) bool {
+			if i, ok := value.(AfterRestoreInterface); ok {
+				db.AddError(i.AfterRestore(tx))
+				return true
+			}
+			return false
+		})
+	}
+}
+
+func findRestoreClause(s *schema.Schema) (gorm.RestoreClauseInterface, bool) {
+	for _, c := range s.RestoreClauses {
+		if rc, ok := c.(gorm.RestoreClauseInterface); ok {
+			return rc, true
+		}
+	}
+	return nil, false
+}
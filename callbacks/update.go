@@ -1,6 +1,7 @@
 package callbacks
 
 import (
+	"fmt"
 	"reflect"
 	"sort"
 
@@ -62,6 +63,7 @@ func Update(config *Config) func(db *gorm.DB) {
 		}
 
 		if db.Statement.Schema != nil {
+			addDiscriminatorCondition(db)
 			for _, c := range db.Statement.Schema.UpdateClauses {
 				db.Statement.AddClause(c)
 			}
@@ -70,6 +72,9 @@ func Update(config *Config) func(db *gorm.DB) {
 		if db.Statement.SQL.Len() == 0 {
 			db.Statement.SQL.Grow(180)
 			db.Statement.AddClauseIfNotExists(clause.Update{})
+			if supportReturning && addGeneratedReturning(db.Statement) {
+				defer delete(db.Statement.Clauses, "RETURNING")
+			}
 			if _, ok := db.Statement.Clauses["SET"]; !ok {
 				if set := ConvertToAssignments(db.Statement); len(set) != 0 {
 					defer delete(db.Statement.Clauses, "SET")
@@ -99,11 +104,35 @@ func Update(config *Config) func(db *gorm.DB) {
 				if db.AddError(err) == nil {
 					db.RowsAffected, _ = result.RowsAffected()
 				}
+
+				if !supportReturning {
+					reloadGeneratedFields(db)
+				}
 			}
 		}
 	}
 }
 
+// addGeneratedReturning reads back generated columns of the updating struct, returns true if added
+func addGeneratedReturning(stmt *gorm.Statement) bool {
+	if _, ok := stmt.Clauses["RETURNING"]; ok || stmt.Schema == nil || stmt.ReflectValue.Kind() != reflect.Struct || !stmt.ReflectValue.CanAddr() {
+		return false
+	}
+
+	var columns []clause.Column
+	for _, field := range stmt.Schema.Fields {
+		if field.GeneratedExpression != "" && field.DBName != "" && field.Readable {
+			columns = append(columns, clause.Column{Name: field.DBName})
+		}
+	}
+
+	if len(columns) > 0 {
+		stmt.AddClause(clause.Returning{Columns: columns})
+		return true
+	}
+	return false
+}
+
 // AfterUpdate after update hooks
 func AfterUpdate(db *gorm.DB) {
 	if db.Error == nil && db.Statement.Schema != nil && !db.Statement.SkipHooks && (db.Statement.Schema.AfterSave || db.Statement.Schema.AfterUpdate) {
@@ -198,6 +227,16 @@ func ConvertToAssignments(stmt *gorm.Statement) (set clause.Set) {
 		}
 		sort.Strings(keys)
 
+		// values of serializer fields are serialized from a record holding the updating values
+		var record reflect.Value
+		if stmt.Schema != nil {
+			record = reflect.New(stmt.Schema.ModelType).Elem()
+			if stmt.ReflectValue.Kind() == reflect.Struct && stmt.ReflectValue.Type() == stmt.Schema.ModelType {
+				record.Set(stmt.ReflectValue)
+			}
+		}
+
+		assigned := map[string]bool{}
 		for _, k := range keys {
 			kv := value[k]
 			if _, ok := kv.(*gorm.DB); ok {
@@ -208,6 +247,14 @@ func ConvertToAssignments(stmt *gorm.Statement) (set clause.Set) {
 				if field := stmt.Schema.LookUpField(k); field != nil {
 					if field.DBName != "" {
 						if v, ok := selectColumns[field.DBName]; (ok && v) || (!ok && !restricted) {
+							_, isExpr := kv.(clause.Expression)
+							if field.Serializer != nil && !isExpr {
+								if err := field.Set(stmt.Context, record, kv); err == nil {
+									kv, _ = field.ValueOf(stmt.Context, record)
+								}
+							}
+
+							assigned[field.DBName] = !isExpr
 							set = append(set, clause.Assignment{Column: clause.Column{Name: field.DBName}, Value: kv})
 							assignValue(field, value[k])
 						}
@@ -223,6 +270,27 @@ func ConvertToAssignments(stmt *gorm.Statement) (set clause.Set) {
 			}
 		}
 
+		// recompute blind indexes of the fields assigned with values
+		if stmt.Schema != nil {
+			for _, dbName := range stmt.Schema.DBNames {
+				field := stmt.Schema.FieldsByDBName[dbName]
+				for _, blindIndex := range field.BlindIndexes {
+					if isValue, ok := assigned[dbName]; !ok {
+						continue
+					} else if _, ok := assigned[blindIndex.DBName]; ok {
+						continue
+					} else if !isValue {
+						stmt.AddError(fmt.Errorf("failed to update blind index %s, %s is updated with expression", blindIndex.Name, field.Name))
+						continue
+					}
+
+					indexValue, _ := blindIndex.ValueOf(stmt.Context, record)
+					assigned[blindIndex.DBName] = true
+					set = append(set, clause.Assignment{Column: clause.Column{Name: blindIndex.DBName}, Value: indexValue})
+				}
+			}
+		}
+
 		if !stmt.SkipHooks && stmt.Schema != nil {
 			for _, dbName := range stmt.Schema.DBNames {
 				field := stmt.Schema.LookUpField(dbName)
@@ -0,0 +1,162 @@
+package callbacks
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Validate validates the fields with tag `validate` written to database by the configured validator, and the values
+// of enum fields
+func Validate(create bool) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil || db.Statement.Schema == nil || (db.Validator == nil && !hasEnumField(db.Statement.Schema)) {
+			return
+		}
+
+		var (
+			stmt                      = db.Statement
+			validationErr             = &gorm.ValidationError{}
+			selectColumns, restricted = stmt.SelectAndOmitColumns(create, !create)
+			validatingSchema          = stmt.Schema
+			validatingValue           = reflect.ValueOf(stmt.Dest)
+		)
+
+		for validatingValue.Kind() == reflect.Ptr {
+			validatingValue = validatingValue.Elem()
+		}
+
+		if !create && validatingValue.Kind() == reflect.Struct && (!validatingValue.CanAddr() || stmt.Dest != stmt.Model) {
+			validatingStmt := &gorm.Statement{DB: stmt.DB}
+			if err := validatingStmt.Parse(stmt.Dest); err != nil {
+				return
+			}
+			validatingSchema = validatingStmt.Schema
+		}
+
+		validate := func(field *schema.Field, value interface{}) {
+			switch value.(type) {
+			case clause.Expression, *gorm.DB:
+				return
+			}
+
+			if len(field.EnumValues) > 0 {
+				if err := validateEnum(field, value, create); err != nil {
+					validationErr.Errors = append(validationErr.Errors, gorm.FieldError{Field: field.Name, DBName: field.DBName, Err: err})
+					return
+				}
+			}
+
+			if db.Validator != nil && hasValidateTag(field) {
+				if err := db.Validator.Validate(stmt.Context, field, value); err != nil {
+					validationErr.Errors = append(validationErr.Errors, gorm.FieldError{Field: field.Name, DBName: field.DBName, Err: err})
+				}
+			}
+		}
+
+		validateMap := func(values map[string]interface{}) {
+			for _, field := range validatingSchema.Fields {
+				if !isValidatingField(field, db.Validator != nil, create, selectColumns, restricted) {
+					continue
+				}
+
+				if value, ok := values[field.DBName]; ok {
+					validate(field, value)
+				} else if value, ok := values[field.Name]; ok {
+					validate(field, value)
+				}
+			}
+		}
+
+		validateStruct := func(rv reflect.Value) {
+			for _, field := range validatingSchema.Fields {
+				if isValidatingField(field, db.Validator != nil, create, selectColumns, restricted) {
+					fieldValue := field.ReflectValueOf(stmt.Context, rv)
+					if _, ok := selectColumns[field.DBName]; ok || create || !fieldValue.IsZero() {
+						validate(field, fieldValue.Interface())
+					}
+				}
+			}
+		}
+
+		switch value := validatingValue.Interface().(type) {
+		case map[string]interface{}:
+			validateMap(value)
+		case []map[string]interface{}:
+			for _, v := range value {
+				validateMap(v)
+			}
+		default:
+			switch validatingValue.Kind() {
+			case reflect.Struct:
+				validateStruct(validatingValue)
+			case reflect.Slice, reflect.Array:
+				for i := 0; i < validatingValue.Len(); i++ {
+					if rv := reflect.Indirect(validatingValue.Index(i)); rv.Kind() == reflect.Struct {
+						validateStruct(rv)
+					}
+				}
+			}
+		}
+
+		if len(validationErr.Errors) > 0 {
+			db.AddError(validationErr)
+		}
+	}
+}
+
+// isValidatingField returns true if the field has tag `validate` or enum values, and is written to database
+func isValidatingField(field *schema.Field, withValidator bool, create bool, selectColumns map[string]bool, restricted bool) bool {
+	if field.DBName == "" || (len(field.EnumValues) == 0 && (!withValidator || !hasValidateTag(field))) {
+		return false
+	}
+
+	if (create && !field.Creatable) || (!create && !field.Updatable) {
+		return false
+	}
+
+	v, ok := selectColumns[field.DBName]
+	return (ok && v) || (!ok && !restricted)
+}
+
+func hasValidateTag(field *schema.Field) bool {
+	tag := field.Tag.Get("validate")
+	return tag != "" && tag != "-"
+}
+
+func hasEnumField(s *schema.Schema) bool {
+	for _, field := range s.Fields {
+		if len(field.EnumValues) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// validateEnum validates value should be one of the enum values, nil value and zero value of field having default
+// value when creating are skipped
+func validateEnum(field *schema.Field, value interface{}, create bool) error {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() || (create && field.HasDefaultValue && rv.IsZero()) {
+		return nil
+	}
+
+	str := fmt.Sprint(rv.Interface())
+	for _, v := range field.EnumValues {
+		if v == str {
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(field.EnumValues, ","))
+}
@@ -238,6 +238,12 @@ func (db *DB) Or(query interface{}, args ...interface{}) (tx *DB) {
 //	db.Joins("Account").Find(&user)
 //	db.Joins("JOIN emails ON emails.user_id = users.id AND emails.email = ?", "jinzhu@example.org").Find(&user)
 //	db.Joins("Account", DB.Select("id").Where("user_id = users.id AND name = ?", "someName").Model(&Account{}))
+//
+// has many and many2many associations are loaded in the same query, rows of the same record are merged when scanning,
+// Limit, Offset apply to the records by limiting their primary keys in a subquery, whose conditions and orders should
+// only reference the current table
+//
+//	db.Joins("Orders").Joins("Languages").Find(&users)
 func (db *DB) Joins(query string, args ...interface{}) (tx *DB) {
 	return joins(db, clause.LeftJoin, query, args...)
 }
@@ -388,6 +394,48 @@ func (db *DB) Preload(query string, args ...interface{}) (tx *DB) {
 	return
 }
 
+// PreloadLimit limits and orders preloaded has one, has many and many2many associations for each parent record when used
+// as an argument of Preload, records are numbered with window function ROW_NUMBER, which are ordered by primary keys if
+// Order is empty, join records of many2many relation are numbered for each parent with the associated records joined,
+// associations are loaded with one limited query for each parent if the dialector doesn't report window function
+// support with WindowFunctionDialectorInterface
+//
+//	// get all posts, and preload 5 latest comments of each post
+//	db.Preload("Comments", gorm.PreloadLimit{Limit: 5, Order: "created_at DESC"}).Find(&posts)
+type PreloadLimit struct {
+	Limit int
+	Order interface{}
+}
+
+// WithCount counts has one, has many and many2many associations of each record with a correlated subquery,
+// the count is scanned into the field of column `<name>_count`, e.g: `OrdersCount`
+//
+//	type User struct {
+//	  Orders      []Order
+//	  OrdersCount int `gorm:"-:migration;->"`
+//	}
+//
+//	// get all users with their paid orders count
+//	db.WithCount("Orders", "state = ?", "paid").Find(&users)
+//
+// associations are aliased by the relation name in the subquery, e.g: `Orders.state`, and the join table of many2many
+// relation is aliased like `Tags__user_tags`
+func (db *DB) WithCount(name string, args ...interface{}) (tx *DB) {
+	return db.WithAggregate(name, "count", "", args...)
+}
+
+// WithAggregate aggregates column of associations of each record with function count, sum, avg, min or max,
+// the result is scanned into the field of column `<name>_<function>_<column>`, e.g: `OrdersSumAmount`
+//
+// aggregates are ignored if the select clause is specified with expressions, e.g: `db.Select("COUNT(?)", ...)`
+//
+//	db.WithAggregate("Orders", "sum", "amount").Find(&users)
+func (db *DB) WithAggregate(name, function, column string, args ...interface{}) (tx *DB) {
+	tx = db.getInstance()
+	tx.Statement.Aggregates = append(tx.Statement.Aggregates, aggregate{Name: name, Function: function, Column: column, Conds: args})
+	return
+}
+
 // Attrs provide attributes used in [FirstOrCreate] or [FirstOrInit]
 //
 // Attrs only adds attributes if the record is not found.
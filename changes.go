@@ -0,0 +1,233 @@
+package gorm
+
+import (
+	"context"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
+)
+
+// Change old and new values of a changed field
+type Change struct {
+	Field *schema.Field
+	Old   interface{}
+	New   interface{}
+}
+
+// Trackable records implementing Trackable get a snapshot of their loaded values after query,
+// embed ChangeTracker into models to implement it
+type Trackable interface {
+	TakeSnapshot(values map[string]interface{})
+	Snapshot() map[string]interface{}
+}
+
+// ChangeTracker keeps the values of a record when it was loaded, which are used to find out the changed fields
+//
+//	type User struct {
+//	  gorm.ChangeTracker
+//	  ID   uint
+//	  Name string
+//	}
+//
+//	db.First(&user)
+//	user.Name = ""
+//	db.SaveChanges(&user) // UPDATE users SET name = '' WHERE id = 1
+type ChangeTracker struct {
+	snapshot map[string]interface{}
+}
+
+// TakeSnapshot implements Trackable interface
+func (t *ChangeTracker) TakeSnapshot(values map[string]interface{}) {
+	t.snapshot = values
+}
+
+// Snapshot implements Trackable interface
+func (t *ChangeTracker) Snapshot() map[string]interface{} {
+	return t.snapshot
+}
+
+// TakeSnapshot takes snapshots of current trackable records
+func (stmt *Statement) TakeSnapshot() {
+	if stmt.Schema == nil {
+		return
+	} else if _, ok := reflect.New(stmt.Schema.ModelType).Interface().(Trackable); !ok {
+		return
+	}
+
+	takeSnapshot := func(rv reflect.Value) {
+		if rv.CanAddr() {
+			if trackable, ok := rv.Addr().Interface().(Trackable); ok {
+				values := make(map[string]interface{}, len(stmt.Schema.DBNames))
+				for _, dbName := range stmt.Schema.DBNames {
+					values[dbName] = deepCopy(changeValueOf(stmt.Context, stmt.Schema.FieldsByDBName[dbName], rv))
+				}
+				trackable.TakeSnapshot(values)
+			}
+		}
+	}
+
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			if rv := reflect.Indirect(stmt.ReflectValue.Index(i)); rv.Kind() == reflect.Struct {
+				takeSnapshot(rv)
+			}
+		}
+	case reflect.Struct:
+		takeSnapshot(stmt.ReflectValue)
+	}
+}
+
+// changeValueOf returns the value of field to track changes, the values of serializer fields are not serialized
+func changeValueOf(ctx context.Context, field *schema.Field, rv reflect.Value) interface{} {
+	if field.Serializer != nil {
+		return field.ReflectValueOf(ctx, rv).Interface()
+	}
+
+	value, _ := field.ValueOf(ctx, rv)
+	return value
+}
+
+// deepCopy copies slices, maps and pointers of value recursively, so in-place mutations of the record,
+// e.g: appending to a slice or editing a map, are found as changes
+func deepCopy(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return deepCopyValue(reflect.ValueOf(value), map[uintptr]reflect.Value{}).Interface()
+}
+
+func deepCopyValue(rv reflect.Value, copied map[uintptr]reflect.Value) reflect.Value {
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return rv
+		}
+		// keep the references of cyclic pointers
+		if v, ok := copied[rv.Pointer()]; ok {
+			return v
+		}
+		result := reflect.New(rv.Type().Elem())
+		copied[rv.Pointer()] = result
+		result.Elem().Set(deepCopyValue(rv.Elem(), copied))
+		return result
+	case reflect.Interface:
+		if rv.IsNil() {
+			return rv
+		}
+		result := reflect.New(rv.Type()).Elem()
+		result.Set(deepCopyValue(rv.Elem(), copied))
+		return result
+	case reflect.Slice:
+		if rv.IsNil() {
+			return rv
+		}
+		result := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			result.Index(i).Set(deepCopyValue(rv.Index(i), copied))
+		}
+		return result
+	case reflect.Map:
+		if rv.IsNil() {
+			return rv
+		}
+		result := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		for _, key := range rv.MapKeys() {
+			result.SetMapIndex(key, deepCopyValue(rv.MapIndex(key), copied))
+		}
+		return result
+	case reflect.Struct:
+		result := reflect.New(rv.Type()).Elem()
+		result.Set(rv)
+		for i := 0; i < rv.NumField(); i++ {
+			if field := result.Field(i); field.CanSet() {
+				field.Set(deepCopyValue(rv.Field(i), copied))
+			}
+		}
+		return result
+	case reflect.Array:
+		result := reflect.New(rv.Type()).Elem()
+		for i := 0; i < rv.Len(); i++ {
+			result.Index(i).Set(deepCopyValue(rv.Index(i), copied))
+		}
+		return result
+	}
+	return rv
+}
+
+// Changes returns changes of current record since its snapshot was taken, keyed by field's db name,
+// returns nil if the record doesn't have a snapshot
+func (stmt *Statement) Changes() map[string]Change {
+	modelValue := stmt.ReflectValue
+	switch modelValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		modelValue = reflect.Indirect(stmt.ReflectValue.Index(stmt.CurDestIndex))
+	}
+
+	if stmt.Schema == nil || modelValue.Kind() != reflect.Struct || !modelValue.CanAddr() {
+		return nil
+	}
+
+	trackable, ok := modelValue.Addr().Interface().(Trackable)
+	if !ok || trackable.Snapshot() == nil {
+		return nil
+	}
+
+	changes := map[string]Change{}
+	snapshot := trackable.Snapshot()
+	for _, dbName := range stmt.Schema.DBNames {
+		field := stmt.Schema.FieldsByDBName[dbName]
+		oldValue, ok := snapshot[dbName]
+		if !ok {
+			continue
+		}
+
+		if newValue := changeValueOf(stmt.Context, field, modelValue); !utils.AssertEqual(oldValue, newValue) {
+			changes[dbName] = Change{Field: field, Old: oldValue, New: newValue}
+		}
+	}
+	return changes
+}
+
+// SaveChanges updates the changed fields of value since it was loaded, including zero values,
+// value should be a pointer to a Trackable struct
+func (db *DB) SaveChanges(value interface{}) (tx *DB) {
+	tx = db.getInstance()
+	if err := tx.Statement.Parse(value); err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	tx.Statement.ReflectValue = reflect.Indirect(reflect.ValueOf(value))
+	if tx.Statement.ReflectValue.Kind() != reflect.Struct {
+		tx.AddError(ErrInvalidValue)
+		return
+	}
+
+	changes := tx.Statement.Changes()
+	if changes == nil {
+		tx.AddError(ErrSnapshotRequired)
+		return
+	} else if len(changes) == 0 {
+		return
+	}
+
+	selects := make([]string, 0, len(changes))
+	for _, change := range changes {
+		selects = append(selects, change.Field.Name)
+	}
+
+	for _, field := range tx.Statement.Schema.Fields {
+		if field.AutoUpdateTime > 0 {
+			if _, ok := changes[field.DBName]; !ok {
+				selects = append(selects, field.Name)
+			}
+		}
+	}
+
+	if tx = tx.Model(value).Select(selects).Updates(value); tx.Error == nil && !tx.DryRun {
+		tx.Statement.TakeSnapshot()
+	}
+	return
+}
@@ -23,6 +23,12 @@ var (
 	ErrModelValueRequired = errors.New("model value required")
 	// ErrModelAccessibleFieldsRequired model accessible fields required
 	ErrModelAccessibleFieldsRequired = errors.New("model accessible fields required")
+	// ErrSnapshotRequired snapshot required
+	ErrSnapshotRequired = errors.New("snapshot required, record should be trackable and loaded from database")
+	// ErrSoftDeleteRequired soft delete field required
+	ErrSoftDeleteRequired = errors.New("soft delete field required")
+	// ErrUnsupportedAggregate unsupported aggregate function
+	ErrUnsupportedAggregate = errors.New("unsupported aggregate function")
 	// ErrSubQueryRequired sub query required
 	ErrSubQueryRequired = errors.New("sub query required")
 	// ErrInvalidData unsupported data
@@ -39,6 +45,8 @@ var (
 	ErrDryRunModeUnsupported = errors.New("dry run mode unsupported")
 	// ErrInvalidDB invalid db
 	ErrInvalidDB = errors.New("invalid db")
+	// ErrUnregisteredSubtype unregistered single table inheritance subtype
+	ErrUnregisteredSubtype = errors.New("unregistered subtype")
 	// ErrInvalidValue invalid value
 	ErrInvalidValue = errors.New("invalid value, should be pointer to struct or slice")
 	// ErrInvalidValueOfLength invalid values do not match length
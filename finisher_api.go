@@ -439,6 +439,49 @@ func (db *DB) Delete(value interface{}, conds ...interface{}) (tx *DB) {
 	return tx.callbacks.Delete().Execute(tx)
 }
 
+// Restore restores soft deleted value matching given conditions, if value contains primary key it will be used as condition.
+// Associations soft deleted together with value could be restored with Select
+//
+//	db.Select("Orders").Restore(&user)
+func (db *DB) Restore(value interface{}, conds ...interface{}) (tx *DB) {
+	tx = db.getInstance()
+	if len(conds) > 0 {
+		if exprs := tx.Statement.BuildCondition(conds[0], conds[1:]...); len(exprs) > 0 {
+			tx.Statement.AddClause(clause.Where{Exprs: exprs})
+		}
+	}
+	tx.Statement.Dest = value
+	return tx.callbacks.Restore().Execute(tx)
+}
+
+type loadOption int
+
+// SkipLoaded skips the records whose associations are already loaded when used as an argument of Load,
+// nested associations of the loaded associations are still loaded, e.g: items of loaded orders
+//
+//	db.Load(&users, "Orders.Items", gorm.SkipLoaded)
+const SkipLoaded loadOption = iota
+
+// Load preloads associations for value which is already loaded, value should be a pointer to struct or slice,
+// supports nested associations, conditions and clause.Associations like Preload
+//
+//	db.Load(&users, "Orders.Items", "state NOT IN (?)", "cancelled")
+func (db *DB) Load(value interface{}, query string, args ...interface{}) (tx *DB) {
+	tx = db.getInstance()
+	conds := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		if arg == SkipLoaded {
+			tx.Statement.Settings.Store("gorm:preload_skip_loaded", true)
+		} else {
+			conds = append(conds, arg)
+		}
+	}
+
+	tx = tx.Preload(query, conds...)
+	tx.Statement.Dest = value
+	return tx.callbacks.Load().Execute(tx)
+}
+
 func (db *DB) Count(count *int64) (tx *DB) {
 	tx = db.getInstance()
 	if tx.Statement.Model == nil {
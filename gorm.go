@@ -50,6 +50,8 @@ type Config struct {
 	CreateBatchSize int
 	// TranslateError enabling error translation
 	TranslateError bool
+	// Validator validates fields with tag `validate` before create and update, validation is disabled if nil
+	Validator Validator
 
 	// ClauseBuilders clause builder
 	ClauseBuilders map[string]clause.ClauseBuilder
@@ -477,6 +479,61 @@ func (db *DB) SetupJoinTable(model interface{}, field string, joinTable interfac
 	return nil
 }
 
+// SetupSubtypes registers single table inheritance subtypes of base, records queried into the slice of interface
+// are instantiated as the subtypes of their discriminator values, register subtypes before querying to avoid
+// unregistered discriminator errors, e.g:
+//
+//	db.SetupSubtypes(&Vehicle{}, &Car{}, &Truck{})
+func (db *DB) SetupSubtypes(base interface{}, subtypes ...interface{}) error {
+	stmt := db.getInstance().Statement
+	if err := stmt.Parse(base); err != nil {
+		return err
+	}
+	baseSchema := stmt.Schema
+
+	for _, subtype := range subtypes {
+		// subtypes are registered to the base when parsed
+		if err := stmt.Parse(subtype); err != nil {
+			return err
+		}
+
+		if stmt.Schema.Inheritance == nil || stmt.Schema.Inheritance.Base != baseSchema {
+			return fmt.Errorf("%s is not a single table inheritance subtype of %s", stmt.Schema.Name, baseSchema.Name)
+		}
+	}
+	return nil
+}
+
+// SetupPolymorphicType registers owner as the model of the polymorphic type value for model's polymorphic belongs to
+// relation field, every owner should be registered before preloading the relation, e.g:
+//
+//	db.SetupPolymorphicType(&Comment{}, "Commentable", "posts", &Post{})
+//	db.SetupPolymorphicType(&Comment{}, "Commentable", "videos", &Video{})
+func (db *DB) SetupPolymorphicType(model interface{}, field string, value string, owner interface{}) error {
+	var (
+		tx   = db.getInstance()
+		stmt = tx.Statement
+	)
+
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+	modelSchema := stmt.Schema
+
+	if err := stmt.Parse(owner); err != nil {
+		return err
+	}
+	ownerSchema := stmt.Schema
+
+	relation, ok := modelSchema.Relationships.Relations[field]
+	if !ok || relation.Type != schema.PolymorphicBelongsTo {
+		return fmt.Errorf("failed to find polymorphic belongs to relation: %s", field)
+	}
+
+	relation.SetupPolymorphicType(value, ownerSchema)
+	return nil
+}
+
 // Use use plugin
 func (db *DB) Use(plugin Plugin) error {
 	name := plugin.Name()
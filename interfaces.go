@@ -44,6 +44,25 @@ type SavePointerDialectorInterface interface {
 	RollbackTo(tx *DB, name string) error
 }
 
+// RecursiveCTEDialectorInterface dialectors implement it to load trees with recursive CTE,
+// trees are loaded level by level if dialectors don't implement it or SupportRecursiveCTE returns false
+type RecursiveCTEDialectorInterface interface {
+	SupportRecursiveCTE() bool
+	// RecursiveWith returns the keyword starting recursive CTE, e.g: `WITH RECURSIVE`, `WITH` for SQL Server
+	RecursiveWith() string
+	// CastToText converts expr to text, e.g: `CAST(expr AS TEXT)`, `CAST(expr AS CHAR)` for MySQL
+	CastToText(expr clause.Expression) clause.Expression
+	// Concat concatenates exprs, e.g: `a || b`, `CONCAT(a, b)` for MySQL
+	Concat(exprs ...clause.Expression) clause.Expression
+}
+
+// WindowFunctionDialectorInterface dialectors implement it to report whether window function is supported,
+// preload with PreloadLimit numbers records with window function ROW_NUMBER, e.g: MySQL 8.0+, MariaDB 10.2+, SQLite 3.25+,
+// or queries for each parent if window function is unsupported, which is assumed if dialectors don't implement it
+type WindowFunctionDialectorInterface interface {
+	SupportWindowFunction() bool
+}
+
 // TxBeginner tx beginner
 type TxBeginner interface {
 	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
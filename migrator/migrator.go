@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -25,6 +26,9 @@ import (
 // -"%$#@789"
 var regFullDataType = regexp.MustCompile(`\D*(\d+)\D?`)
 
+// regQuotedValue matches quoted values in check constraint definition
+var regQuotedValue = regexp.MustCompile(`'((?:[^']|'')*)'`)
+
 // TODO:? Create const vars for raw sql queries ?
 
 var _ gorm.Migrator = (*Migrator)(nil)
@@ -56,6 +60,27 @@ type GormDataTypeInterface interface {
 	GormDBDataType(*gorm.DB, *schema.Field) string
 }
 
+// EnumDataTypeInterface dialectors supporting native enum data type implement it, e.g: `ENUM('a','b')`, check
+// constraints are used for enum fields otherwise
+type EnumDataTypeInterface interface {
+	EnumDataTypeOf(*schema.Field) string
+}
+
+// CheckConstraintDefinitionInterface dialectors implement it to return the definition of check constraint of table,
+// e.g: `CHECK (status IN ('draft','published'))`, enum check constraints are recreated when the enum values changed,
+// which are kept if the dialector doesn't implement it
+type CheckConstraintDefinitionInterface interface {
+	CheckConstraintDefinition(db *gorm.DB, table, name string) (string, error)
+}
+
+// GeneratedColumnInterface dialectors implement it to support generated columns, it returns the data type of generated
+// column, e.g: `AS (a + b) PERSISTED`, and the expression of generated column of table, generated columns are recreated
+// with DropColumn, AddColumn of dialector's migrator when the expression changed
+type GeneratedColumnInterface interface {
+	GeneratedDataTypeOf(*schema.Field) string
+	GeneratedExpressionOf(db *gorm.DB, table, column string) (string, error)
+}
+
 // RunWithValue run migration with statement value
 func (m Migrator) RunWithValue(value interface{}, fc func(*gorm.Statement) error) error {
 	stmt := &gorm.Statement{DB: m.DB}
@@ -82,13 +107,33 @@ func (m Migrator) DataTypeOf(field *schema.Field) string {
 		}
 	}
 
+	if len(field.EnumValues) > 0 {
+		if enumDataTyper, ok := m.Dialector.(EnumDataTypeInterface); ok {
+			if dataType := enumDataTyper.EnumDataTypeOf(field); dataType != "" {
+				return dataType
+			}
+		}
+	}
+
 	return m.Dialector.DataTypeOf(field)
 }
 
+// isNativeEnum returns true if the enum field uses native enum data type instead of check constraint
+func (m Migrator) isNativeEnum(field *schema.Field) bool {
+	if enumDataTyper, ok := m.Dialector.(EnumDataTypeInterface); ok && len(field.EnumValues) > 0 {
+		return enumDataTyper.EnumDataTypeOf(field) != ""
+	}
+	return false
+}
+
 // FullDataTypeOf returns field's db full data type
 func (m Migrator) FullDataTypeOf(field *schema.Field) (expr clause.Expr) {
 	expr.SQL = m.DataTypeOf(field)
 
+	if generator, ok := m.Dialector.(GeneratedColumnInterface); ok && field.GeneratedExpression != "" {
+		expr.SQL = generator.GeneratedDataTypeOf(field)
+	}
+
 	if field.NotNull {
 		expr.SQL += " NOT NULL"
 	}
@@ -173,6 +218,10 @@ func (m Migrator) AutoMigrate(values ...interface{}) error {
 				}
 
 				for _, chk := range parseCheckConstraints {
+					if chk.Enum && m.isNativeEnum(chk.Field) {
+						continue
+					}
+
 					if !queryTx.Migrator().HasConstraint(value, chk.Name) {
 						if err := execTx.Migrator().CreateConstraint(value, chk.Name); err != nil {
 							return err
@@ -219,6 +268,10 @@ func (m Migrator) CreateTable(values ...interface{}) error {
 			for _, dbName := range stmt.Schema.DBNames {
 				field := stmt.Schema.FieldsByDBName[dbName]
 				if !field.IgnoreMigration {
+					if err = m.checkGeneratedColumn(field); err != nil {
+						return err
+					}
+
 					createTableSQL += "? ?"
 					hasPrimaryKeyInDataType = hasPrimaryKeyInDataType || strings.Contains(strings.ToUpper(m.DataTypeOf(field)), "PRIMARY KEY")
 					values = append(values, clause.Column{Name: dbName}, m.DB.Migrator().FullDataTypeOf(field))
@@ -283,8 +336,11 @@ func (m Migrator) CreateTable(values ...interface{}) error {
 			}
 
 			for _, chk := range stmt.Schema.ParseCheckConstraints() {
+				if chk.Enum && m.isNativeEnum(chk.Field) {
+					continue
+				}
 				createTableSQL += "CONSTRAINT ? CHECK (?),"
-				values = append(values, clause.Column{Name: chk.Name}, clause.Expr{SQL: chk.Constraint})
+				values = append(values, clause.Column{Name: chk.Name}, chk.Expression())
 			}
 
 			createTableSQL = strings.TrimSuffix(createTableSQL, ",")
@@ -368,10 +424,22 @@ func (m Migrator) AddColumn(value interface{}, name string) error {
 		}
 
 		if !f.IgnoreMigration {
-			return m.DB.Exec(
+			if err := m.checkGeneratedColumn(f); err != nil {
+				return err
+			}
+
+			if err := m.DB.Exec(
 				"ALTER TABLE ? ADD ? ?",
 				m.CurrentTable(stmt), clause.Column{Name: f.DBName}, m.DB.Migrator().FullDataTypeOf(f),
-			).Error
+			).Error; err != nil {
+				return err
+			}
+
+			if len(f.EnumValues) > 0 && !m.isNativeEnum(f) {
+				if name := m.DB.NamingStrategy.CheckerName(stmt.Table, f.DBName+"_enum"); !m.DB.Migrator().HasConstraint(value, name) {
+					return m.DB.Migrator().CreateConstraint(value, name)
+				}
+			}
 		}
 
 		return nil
@@ -449,6 +517,10 @@ func (m Migrator) MigrateColumn(value interface{}, field *schema.Field, columnTy
 		return nil
 	}
 
+	if field.GeneratedExpression != "" {
+		return m.migrateGeneratedColumn(value, field)
+	}
+
 	// found, smart migrate
 	fullDataType := strings.TrimSpace(strings.ToLower(m.DB.Migrator().FullDataTypeOf(field).SQL))
 	realDataType := strings.ToLower(columnType.DatabaseTypeName())
@@ -536,6 +608,13 @@ func (m Migrator) MigrateColumn(value interface{}, field *schema.Field, columnTy
 		}
 	}
 
+	// check enum values of native enum type
+	if m.isNativeEnum(field) {
+		if columnType, ok := columnType.ColumnType(); ok && !strings.EqualFold(columnType, m.DataTypeOf(field)) {
+			alterColumn = true
+		}
+	}
+
 	if alterColumn {
 		if err := m.DB.Migrator().AlterColumn(value, field.DBName); err != nil {
 			return err
@@ -546,9 +625,104 @@ func (m Migrator) MigrateColumn(value interface{}, field *schema.Field, columnTy
 		return err
 	}
 
+	if len(field.EnumValues) > 0 && !m.isNativeEnum(field) {
+		return m.migrateColumnEnum(value, field)
+	}
+
+	return nil
+}
+
+// checkGeneratedColumn returns ErrUnsupportedDriver for generated column if the dialector doesn't implement
+// GeneratedColumnInterface, the syntax of generated columns varies by databases
+func (m Migrator) checkGeneratedColumn(field *schema.Field) error {
+	if _, ok := m.Dialector.(GeneratedColumnInterface); !ok && field.GeneratedExpression != "" {
+		return fmt.Errorf("%w: generated column %s", gorm.ErrUnsupportedDriver, field.Name)
+	}
 	return nil
 }
 
+// migrateGeneratedColumn recreates the generated column if its expression returned by GeneratedColumnInterface of
+// dialector changed, generated columns can't be altered, skipped if the dialector doesn't implement it
+func (m Migrator) migrateGeneratedColumn(value interface{}, field *schema.Field) error {
+	generator, ok := m.Dialector.(GeneratedColumnInterface)
+	if !ok {
+		return nil
+	}
+
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		tx := m.DB.Session(&gorm.Session{NewDB: true, Logger: m.DB.Logger.LogMode(logger.Silent)})
+		expression, _ := generator.GeneratedExpressionOf(tx, stmt.Table, field.DBName)
+		if expression == "" || normalizeGeneratedExpression(expression) == normalizeGeneratedExpression(field.GeneratedExpression) {
+			// unable to get the expression or not changed
+			return nil
+		}
+
+		if err := m.DB.Migrator().DropColumn(value, field.DBName); err != nil {
+			return err
+		}
+		return m.DB.Migrator().AddColumn(value, field.DBName)
+	})
+}
+
+// regTypeCast matches type casts of Postgres, e.g: `::double precision`
+var regTypeCast = regexp.MustCompile(`::[a-z_ ]+`)
+
+// normalizeGeneratedExpression removes quotes, parentheses, spaces and type casts added by databases, e.g: MySQL returns
+// (`price` * `quantity`) for price * quantity
+func normalizeGeneratedExpression(expression string) string {
+	expression = regTypeCast.ReplaceAllString(strings.ToLower(expression), "")
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '`', '"', '[', ']', '(', ')', ' ', '\t', '\n', '\r':
+			return -1
+		}
+		return r
+	}, expression)
+}
+
+// migrateColumnEnum recreates the enum check constraint of field if the enum values changed
+func (m Migrator) migrateColumnEnum(value interface{}, field *schema.Field) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		name := m.DB.NamingStrategy.CheckerName(stmt.Table, field.DBName+"_enum")
+		if _, ok := stmt.Schema.ParseCheckConstraints()[name]; !ok {
+			return nil
+		}
+
+		definition := m.checkConstraintDefinition(stmt.Table, name)
+		if definition == "" {
+			// not exists or unable to get the definition, missing constraint will be created by AutoMigrate
+			return nil
+		}
+
+		var values []string
+		for _, matches := range regQuotedValue.FindAllStringSubmatch(definition, -1) {
+			values = append(values, strings.ReplaceAll(matches[1], "''", "'"))
+		}
+
+		expected := append([]string{}, field.EnumValues...)
+		sort.Strings(values)
+		sort.Strings(expected)
+		if strings.Join(values, "\x00") == strings.Join(expected, "\x00") {
+			return nil
+		}
+
+		if err := m.DB.Migrator().DropConstraint(value, name); err != nil {
+			return err
+		}
+		return m.DB.Migrator().CreateConstraint(value, name)
+	})
+}
+
+// checkConstraintDefinition returns the definition of check constraint of table with CheckConstraintDefinitionInterface
+// of dialector, returns empty string if not found or unsupported
+func (m Migrator) checkConstraintDefinition(table, name string) (definition string) {
+	if definer, ok := m.Dialector.(CheckConstraintDefinitionInterface); ok {
+		tx := m.DB.Session(&gorm.Session{NewDB: true, Logger: m.DB.Logger.LogMode(logger.Silent)})
+		definition, _ = definer.CheckConstraintDefinition(tx, table, name)
+	}
+	return definition
+}
+
 func (m Migrator) MigrateColumnUnique(value interface{}, field *schema.Field, columnType gorm.ColumnType) error {
 	unique, ok := columnType.Unique()
 	if !ok || field.PrimaryKey {
@@ -873,6 +1047,7 @@ func (m Migrator) ReorderModels(values []interface{}, autoAdd bool) (results []i
 		orderedModelNamesMap          = map[string]bool{}
 		parsedSchemas                 = map[*schema.Schema]bool{}
 		valuesMap                     = map[string]Dependency{}
+		sharedTableValues             = map[string][]interface{}{}
 		insertIntoOrderedList         func(name string)
 		parseDependence               func(value interface{}, addToList bool)
 	)
@@ -919,6 +1094,12 @@ func (m Migrator) ReorderModels(values []interface{}, autoAdd bool) (results []i
 			}
 		}
 
+		// single table inheritance subtypes sharing the table are migrated together to create the union of their columns
+		if v, ok := valuesMap[dep.Schema.Table]; ok && v.Schema != dep.Schema && (dep.Schema.Inheritance != nil || v.Schema.Inheritance != nil) {
+			sharedTableValues[dep.Schema.Table] = append(sharedTableValues[dep.Schema.Table], value)
+			return
+		}
+
 		valuesMap[dep.Schema.Table] = dep
 
 		if addToList {
@@ -961,6 +1142,7 @@ func (m Migrator) ReorderModels(values []interface{}, autoAdd bool) (results []i
 
 	for _, name := range orderedModelNames {
 		results = append(results, valuesMap[name].Statement.Dest)
+		results = append(results, sharedTableValues[name]...)
 	}
 	return
 }
@@ -3,7 +3,9 @@ package gorm
 import (
 	"database/sql"
 	"database/sql/driver"
+	"fmt"
 	"reflect"
+	"strconv"
 	"time"
 
 	"gorm.io/gorm/schema"
@@ -111,6 +113,284 @@ func (db *DB) scanIntoStruct(rows Rows, reflectValue reflect.Value, values []int
 	}
 }
 
+// scanIntoSubtypes scans rows into the slice of interface, records are instantiated as the single table inheritance
+// subtypes of their discriminator values, or the model of sch if the discriminator is empty, returns
+// ErrUnregisteredSubtype if no subtype is registered for the value
+func (db *DB) scanIntoSubtypes(rows Rows, reflectValue reflect.Value, initialized bool, values []interface{}, columns []string, sch *schema.Schema) reflect.Value {
+	var (
+		elemType         = reflectValue.Type().Elem()
+		discriminator    = sch.Discriminator()
+		discriminatorIdx = -1
+	)
+
+	for idx, column := range columns {
+		if column == discriminator {
+			discriminatorIdx = idx
+		}
+	}
+
+	for initialized || rows.Next() {
+		initialized = false
+		for idx := range values {
+			values[idx] = new(interface{})
+		}
+
+		db.RowsAffected++
+		db.AddError(rows.Scan(values...))
+
+		subtype := sch
+		if discriminatorIdx >= 0 {
+			var value string
+			switch v := *values[discriminatorIdx].(*interface{}); v := v.(type) {
+			case nil:
+			case []byte:
+				value = string(v)
+			default:
+				value = fmt.Sprint(v)
+			}
+
+			if value != "" && (sch.Inheritance == nil || value != sch.Inheritance.Value) {
+				if subtype = sch.LookUpSubtype(value); subtype == nil {
+					db.AddError(fmt.Errorf("%w: discriminator value %s of %s, register it with SetupSubtypes", ErrUnregisteredSubtype, value, sch.Name))
+					continue
+				}
+			}
+		}
+
+		elem := reflect.New(subtype.ModelType)
+		for idx, column := range columns {
+			if field := subtype.LookUpField(column); field != nil && field.Readable {
+				value := *values[idx].(*interface{})
+				// scan with the value of field's pool if it is a scanner, e.g. serializer
+				fieldValue := field.NewValuePool.Get()
+				if scanner, ok := fieldValue.(sql.Scanner); ok {
+					if db.AddError(scanner.Scan(value)) == nil {
+						db.AddError(field.Set(db.Statement.Context, elem, fieldValue))
+					}
+				} else {
+					db.AddError(field.Set(db.Statement.Context, elem, value))
+				}
+				field.NewValuePool.Put(fieldValue)
+			}
+		}
+
+		if elem.Type().AssignableTo(elemType) {
+			reflectValue = reflect.Append(reflectValue, elem)
+		} else if elem.Elem().Type().AssignableTo(elemType) {
+			reflectValue = reflect.Append(reflectValue, elem.Elem())
+		} else {
+			db.AddError(fmt.Errorf("%w: %v doesn't implement %v", ErrInvalidData, elem.Type(), elemType))
+		}
+	}
+	return reflectValue
+}
+
+// joinedCollections returns joined has many, many2many relations and indexes of their primary key columns,
+// which are used to merge rows of the same record when scanning
+func joinedCollections(fields []*schema.Field, joinFields [][]*schema.Field) map[string][]int {
+	var collections map[string][]int
+	for idx, relFields := range joinFields {
+		for i := 0; i < len(relFields)-1; i++ {
+			if relFields[i].IndirectFieldType.Kind() != reflect.Slice {
+				continue
+			}
+
+			names := make([]string, 0, i+1)
+			for _, relField := range relFields[:i+1] {
+				names = append(names, relField.Name)
+			}
+
+			name := utils.JoinNestedRelationNames(names)
+			if collections == nil {
+				collections = map[string][]int{}
+			}
+
+			if i == len(relFields)-2 && fields[idx].PrimaryKey {
+				collections[name] = append(collections[name], idx)
+			} else if _, ok := collections[name]; !ok {
+				collections[name] = nil
+			}
+		}
+	}
+	return collections
+}
+
+// joinedRecord record of joined relation in current row
+type joinedRecord struct {
+	value reflect.Value
+	key   string
+}
+
+// scanIntoCollections scans rows with joined has many, many2many relations, rows of the same record are merged into one,
+// and joined records are appended into its collection fields, returns records for slice, only the first record is scanned for struct
+func (db *DB) scanIntoCollections(rows Rows, reflectValue reflect.Value, initialized bool, values []interface{}, fields []*schema.Field, joinFields [][]*schema.Field, collections map[string][]int) reflect.Value {
+	var (
+		isSlice      = reflectValue.Kind() == reflect.Slice
+		elemType     = reflectValue.Type()
+		primaryIdxes []int
+		records      []reflect.Value
+		recordsMap   = map[string]reflect.Value{}
+		childrenMap  = map[string]int{}
+	)
+
+	if isSlice {
+		elemType = elemType.Elem()
+	}
+
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	for idx, field := range fields {
+		if field != nil && len(joinFields[idx]) == 0 && field.PrimaryKey {
+			primaryIdxes = append(primaryIdxes, idx)
+		}
+	}
+
+	for row := 0; initialized || rows.Next(); row++ {
+		initialized = false
+		for idx, field := range fields {
+			if field != nil {
+				values[idx] = field.NewValuePool.Get()
+			}
+		}
+		db.AddError(rows.Scan(values...))
+
+		key, isNull := scannedKey(values, primaryIdxes)
+		if isNull {
+			key = strconv.Itoa(row)
+		}
+
+		record, ok := recordsMap[key]
+		if !ok && (isSlice || len(records) == 0) {
+			if isSlice {
+				record = reflect.New(elemType)
+			} else {
+				record = reflectValue.Addr()
+			}
+
+			db.RowsAffected++
+			records = append(records, record)
+			recordsMap[key] = record
+			for idx, field := range fields {
+				if field != nil && len(joinFields[idx]) == 0 {
+					db.AddError(field.Set(db.Statement.Context, record, values[idx]))
+				}
+			}
+		}
+
+		if record.IsValid() {
+			joinedRecords := map[string]joinedRecord{}
+			for idx, field := range fields {
+				if field != nil && len(joinFields[idx]) > 0 {
+					if relValue, ok := joinedValue(db, record, key, joinFields[idx], values, idx, collections, joinedRecords, childrenMap); ok {
+						db.AddError(field.Set(db.Statement.Context, relValue, values[idx]))
+					}
+				}
+			}
+		}
+
+		// release data to pool
+		for idx, field := range fields {
+			if field != nil {
+				field.NewValuePool.Put(values[idx])
+			}
+		}
+	}
+
+	if isSlice {
+		for _, record := range records {
+			if isPtr {
+				reflectValue = reflect.Append(reflectValue, record)
+			} else {
+				reflectValue = reflect.Append(reflectValue, record.Elem())
+			}
+		}
+	}
+	return reflectValue
+}
+
+// joinedValue returns the value of joined relation in current row to set field relFields[len(relFields)-1],
+// new records are appended into collection fields, returns false if the joined record is null
+func joinedValue(db *DB, record reflect.Value, key string, relFields []*schema.Field, values []interface{}, idx int,
+	collections map[string][]int, joinedRecords map[string]joinedRecord, childrenMap map[string]int,
+) (reflect.Value, bool) {
+	names := make([]string, 0, len(relFields)-1)
+	for _, relField := range relFields[:len(relFields)-1] {
+		names = append(names, relField.Name)
+		name := utils.JoinNestedRelationNames(names)
+		if joined, ok := joinedRecords[name]; ok {
+			if !joined.value.IsValid() {
+				return joined.value, false
+			}
+			record, key = joined.value, joined.key
+			continue
+		}
+
+		relValue := relField.ReflectValueOf(db.Statement.Context, record)
+		switch relValue.Kind() {
+		case reflect.Slice:
+			childKey, isNull := scannedKey(values, collections[name])
+			if isNull && len(collections[name]) > 0 {
+				joinedRecords[name] = joinedRecord{}
+				return reflect.Value{}, false
+			}
+
+			key = utils.ToStringKey(key, name, childKey)
+			childIdx, ok := childrenMap[key]
+			if !ok || len(collections[name]) == 0 {
+				if relValue.Type().Elem().Kind() == reflect.Ptr {
+					relValue.Set(reflect.Append(relValue, reflect.New(relValue.Type().Elem().Elem())))
+				} else {
+					relValue.Set(reflect.Append(relValue, reflect.New(relValue.Type().Elem()).Elem()))
+				}
+				childIdx = relValue.Len() - 1
+				childrenMap[key] = childIdx
+			}
+			relValue = relValue.Index(childIdx)
+		case reflect.Ptr:
+			if value := reflect.ValueOf(values[idx]).Elem(); value.Kind() == reflect.Ptr && value.IsNil() {
+				return reflect.Value{}, false
+			}
+
+			if relValue.IsNil() {
+				relValue.Set(reflect.New(relValue.Type().Elem()))
+			}
+		}
+
+		joinedRecords[name] = joinedRecord{value: relValue, key: key}
+		record = relValue
+	}
+	return record, true
+}
+
+// scannedKey returns the key of scanned values, returns true if all of them are null
+func scannedKey(values []interface{}, idxes []int) (string, bool) {
+	if len(idxes) == 0 {
+		return "", true
+	}
+
+	var (
+		isNull    = true
+		keyValues = make([]interface{}, 0, len(idxes))
+	)
+	for _, idx := range idxes {
+		value := reflect.Indirect(reflect.Indirect(reflect.ValueOf(values[idx])))
+		if value.IsValid() {
+			keyValues = append(keyValues, value.Interface())
+			if valuer, ok := value.Interface().(driver.Valuer); !ok {
+				isNull = false
+			} else if v, _ := valuer.Value(); v != nil {
+				isNull = false
+			}
+		} else {
+			keyValues = append(keyValues, nil)
+		}
+	}
+	return utils.ToStringKey(keyValues...), isNull
+}
+
 // ScanMode scan data mode
 type ScanMode uint8
 
@@ -181,6 +461,7 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 		var (
 			fields       = make([]*schema.Field, len(columns))
 			joinFields   [][]*schema.Field
+			collections  map[string][]int
 			sch          = db.Statement.Schema
 			reflectValue = db.Statement.ReflectValue
 		)
@@ -262,6 +543,10 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 						values[idx] = &sql.RawBytes{}
 					}
 				}
+
+				if len(joinFields) > 0 {
+					collections = joinedCollections(fields, joinFields)
+				}
 			}
 		}
 
@@ -272,6 +557,14 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 				isArrayKind = reflectValue.Kind() == reflect.Array
 			)
 
+			// instantiate single table inheritance subtypes into slice of interface
+			if reflectValueType.Kind() == reflect.Interface && !isArrayKind && sch != nil && sch.Discriminator() != "" {
+				if !update || reflectValue.Len() == 0 {
+					db.Statement.ReflectValue.Set(db.scanIntoSubtypes(rows, reflect.MakeSlice(reflectValue.Type(), 0, 20), initialized, values, columns, sch))
+				}
+				break
+			}
+
 			if !update || reflectValue.Len() == 0 {
 				update = false
 				// if the slice cap is externally initialized, the externally initialized slice is directly used here
@@ -283,6 +576,11 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 				}
 			}
 
+			if len(collections) > 0 && !update && !isArrayKind {
+				db.Statement.ReflectValue.Set(db.scanIntoCollections(rows, reflectValue, initialized, values, fields, joinFields, collections))
+				break
+			}
+
 			for initialized || rows.Next() {
 			BEGIN:
 				initialized = false
@@ -324,7 +622,9 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 				db.Statement.ReflectValue.Set(reflectValue)
 			}
 		case reflect.Struct, reflect.Ptr:
-			if initialized || rows.Next() {
+			if len(collections) > 0 && reflectValue.Kind() == reflect.Struct && reflectValue.CanAddr() {
+				db.scanIntoCollections(rows, reflectValue, initialized, values, fields, joinFields, collections)
+			} else if initialized || rows.Next() {
 				db.scanIntoStruct(rows, reflectValue, values, fields, joinFields)
 			}
 		default:
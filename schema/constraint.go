@@ -13,13 +13,30 @@ var regEnLetterAndMidline = regexp.MustCompile("^[A-Za-z-_]+$")
 type CheckConstraint struct {
 	Name       string
 	Constraint string // length(phone) >= 10
+	Enum       bool   // constraint of the enum values of field
 	*Field
 }
 
 func (chk *CheckConstraint) GetName() string { return chk.Name }
 
 func (chk *CheckConstraint) Build() (sql string, vars []interface{}) {
-	return "CONSTRAINT ? CHECK (?)", []interface{}{clause.Column{Name: chk.Name}, clause.Expr{SQL: chk.Constraint}}
+	return "CONSTRAINT ? CHECK (?)", []interface{}{clause.Column{Name: chk.Name}, chk.Expression()}
+}
+
+// Expression returns the check expression, the column of enum check is quoted, e.g: `role` IN ('admin','member')
+func (chk *CheckConstraint) Expression() clause.Expression {
+	if chk.Enum && chk.Field != nil {
+		return clause.Expr{SQL: "? IN (?)", Vars: []interface{}{clause.Column{Name: chk.Field.DBName}, clause.Expr{SQL: enumValuesSQL(chk.Field.EnumValues)}}}
+	}
+	return clause.Expr{SQL: chk.Constraint}
+}
+
+func enumValuesSQL(enumValues []string) string {
+	values := make([]string, len(enumValues))
+	for idx, value := range enumValues {
+		values[idx] = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	return strings.Join(values, ",")
 }
 
 // ParseCheckConstraints parse schema check constraints
@@ -38,6 +55,11 @@ func (schema *Schema) ParseCheckConstraints() map[string]CheckConstraint {
 				checks[name] = CheckConstraint{Name: name, Constraint: chk, Field: field}
 			}
 		}
+
+		if len(field.EnumValues) > 0 {
+			name := schema.namer.CheckerName(schema.Table, field.DBName+"_enum")
+			checks[name] = CheckConstraint{Name: name, Constraint: field.DBName + " IN (" + enumValuesSQL(field.EnumValues) + ")", Enum: true, Field: field}
+		}
 	}
 	return checks
 }
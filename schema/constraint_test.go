@@ -55,6 +55,31 @@ func TestParseCheck(t *testing.T) {
 	}
 }
 
+func TestParseEnumCheck(t *testing.T) {
+	type UserEnum struct {
+		Role string `enum:"admin, member,o'neil"`
+	}
+
+	user, err := schema.Parse(&UserEnum{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse user enum, got error %v", err)
+	}
+
+	checks := user.ParseCheckConstraints()
+
+	result := schema.CheckConstraint{
+		Name:       "chk_user_enums_role_enum",
+		Constraint: "role IN ('admin','member','o''neil')",
+		Enum:       true,
+	}
+
+	v, ok := checks[result.Name]
+	if !ok {
+		t.Fatalf("Failed to found enum check %v from parsed checks %+v", result.Name, checks)
+	}
+	tests.AssertObjEqual(t, result, v, "Name", "Constraint", "Enum")
+}
+
 func TestParseUniqueConstraints(t *testing.T) {
 	type UserUnique struct {
 		Name1 string `gorm:"unique"`
@@ -88,6 +88,11 @@ type Field struct {
 	ValueOf                func(context.Context, reflect.Value) (value interface{}, zero bool)
 	Set                    func(context.Context, reflect.Value, interface{}) error
 	Serializer             SerializerInterface
+	BlindIndexes           []*Field // blind index fields referring the field, see EncryptedSerializer
+	Generator              GeneratorInterface
+	EnumValues             []string
+	GeneratedExpression    string // expression of the column generated by database
+	GeneratedStored        bool
 	NewValuePool           FieldNewValuePool
 
 	// In some db (e.g. MySQL), Unique and UniqueIndex are indistinguishable.
@@ -201,6 +206,14 @@ func (schema *Schema) ParseField(fieldStruct reflect.StructField) *Field {
 		}
 	}
 
+	if generatorName := field.TagSettings["GENERATOR"]; generatorName != "" {
+		if generator, ok := GetGenerator(generatorName); ok {
+			field.Generator = generator
+		} else {
+			schema.err = fmt.Errorf("invalid generator type %v", generatorName)
+		}
+	}
+
 	if num, ok := field.TagSettings["AUTOINCREMENTINCREMENT"]; ok {
 		field.AutoIncrementIncrement, _ = strconv.ParseInt(num, 10, 64)
 	}
@@ -287,6 +300,18 @@ func (schema *Schema) ParseField(fieldStruct reflect.StructField) *Field {
 		field.DataType = DataType(dataTyper.GormDataType())
 	}
 
+	if dataTyper, ok := field.Serializer.(GormDataTypeInterface); ok {
+		field.DataType = DataType(dataTyper.GormDataType())
+	}
+
+	if enum, ok := fieldValue.Interface().(EnumInterface); ok {
+		field.EnumValues = enum.EnumValues()
+	} else if values := field.Tag.Get("enum"); values != "" {
+		for _, value := range strings.Split(values, ",") {
+			field.EnumValues = append(field.EnumValues, strings.TrimSpace(value))
+		}
+	}
+
 	if v, ok := field.TagSettings["AUTOCREATETIME"]; (ok && utils.CheckTruth(v)) || (!ok && field.Name == "CreatedAt" && (field.DataType == Time || field.DataType == Int || field.DataType == Uint)) {
 		if field.DataType == Time {
 			field.AutoCreateTime = UnixTime
@@ -382,6 +407,15 @@ func (schema *Schema) ParseField(fieldStruct reflect.StructField) *Field {
 		}
 	}
 
+	// generated column, e.g: `gorm:"generated:price * quantity;stored"`, its value is assigned by database
+	if expression := field.TagSettings["GENERATED"]; expression != "" {
+		field.GeneratedExpression = strings.TrimSpace(expression)
+		_, field.GeneratedStored = field.TagSettings["STORED"]
+		field.Creatable = false
+		field.Updatable = false
+		field.HasDefaultValue = true
+	}
+
 	// Normal anonymous field or having `EMBEDDED` tag
 	if _, ok := field.TagSettings["EMBEDDED"]; ok || (field.GORMDataType != Time && field.GORMDataType != Bytes && !isValuer &&
 		fieldStruct.Anonymous && (field.Creatable || field.Updatable || field.Readable)) {
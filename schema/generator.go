@@ -0,0 +1,196 @@
+package schema
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+var generatorMap = sync.Map{}
+
+// RegisterGenerator register generator
+func RegisterGenerator(name string, generator GeneratorInterface) {
+	generatorMap.Store(strings.ToLower(name), generator)
+}
+
+// GetGenerator get generator
+func GetGenerator(name string) (generator GeneratorInterface, ok bool) {
+	v, ok := generatorMap.Load(strings.ToLower(name))
+	if ok {
+		generator, ok = v.(GeneratorInterface)
+	}
+	return generator, ok
+}
+
+func init() {
+	RegisterGenerator("uuid", UUIDGenerator{Version: 4})
+	RegisterGenerator("uuidv4", UUIDGenerator{Version: 4})
+	RegisterGenerator("uuidv7", UUIDGenerator{Version: 7})
+	RegisterGenerator("ulid", ULIDGenerator{})
+	snowflake, _ := NewSnowflakeGenerator(0)
+	RegisterGenerator("snowflake", snowflake)
+}
+
+// GeneratorInterface generates value for zero-valued field with tag `generator` when creating, e.g:
+//
+//	type User struct {
+//		ID   string `gorm:"primaryKey;generator:uuidv7"`
+//		Name string
+//	}
+type GeneratorInterface interface {
+	Generate(ctx context.Context, field *Field) (interface{}, error)
+}
+
+// UUIDGenerator generates random UUID (version 4) or time-ordered UUID (version 7), the value is generated as
+// string for string fields, as bytes for []byte, [16]byte fields
+type UUIDGenerator struct {
+	Version int
+}
+
+// Generate implements generator interface
+func (g UUIDGenerator) Generate(ctx context.Context, field *Field) (interface{}, error) {
+	var uuid [16]byte
+	if _, err := rand.Read(uuid[:]); err != nil {
+		return nil, err
+	}
+
+	switch g.Version {
+	case 4:
+	case 7:
+		// 48 bits big-endian unix timestamp in milliseconds, followed by random bits
+		ms := uint64(time.Now().UnixMilli())
+		for i := 0; i < 6; i++ {
+			uuid[i] = byte(ms >> (40 - 8*i))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported uuid version %d", g.Version)
+	}
+
+	uuid[6] = uuid[6]&0x0f | byte(g.Version)<<4
+	uuid[8] = uuid[8]&0x3f | 0x80
+
+	switch field.IndirectFieldType.Kind() {
+	case reflect.String:
+		buf := make([]byte, 36)
+		hex.Encode(buf, uuid[:4])
+		buf[8] = '-'
+		hex.Encode(buf[9:13], uuid[4:6])
+		buf[13] = '-'
+		hex.Encode(buf[14:18], uuid[6:8])
+		buf[18] = '-'
+		hex.Encode(buf[19:23], uuid[8:10])
+		buf[23] = '-'
+		hex.Encode(buf[24:], uuid[10:])
+		return string(buf), nil
+	default:
+		return generatedBytes(field, uuid[:])
+	}
+}
+
+// crockfordBase32 alphabet of ULID
+const crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULIDGenerator generates lexicographically sortable ULID, the value is generated as 26 characters string for string
+// fields, as bytes for []byte, [16]byte fields
+type ULIDGenerator struct{}
+
+// Generate implements generator interface
+func (ULIDGenerator) Generate(ctx context.Context, field *Field) (interface{}, error) {
+	var ulid [16]byte
+	if _, err := rand.Read(ulid[6:]); err != nil {
+		return nil, err
+	}
+
+	ms := uint64(time.Now().UnixMilli())
+	for i := 0; i < 6; i++ {
+		ulid[i] = byte(ms >> (40 - 8*i))
+	}
+
+	if field.IndirectFieldType.Kind() != reflect.String {
+		return generatedBytes(field, ulid[:])
+	}
+
+	// encode 128 bits into 26 characters of 5 bits, the first character holds the highest 3 bits
+	hi, lo := binary.BigEndian.Uint64(ulid[:8]), binary.BigEndian.Uint64(ulid[8:])
+	buf := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		buf[i] = crockfordBase32[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(buf), nil
+}
+
+func generatedBytes(field *Field, value []byte) (interface{}, error) {
+	switch fieldType := field.IndirectFieldType; {
+	case fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() == reflect.Uint8:
+		return reflect.ValueOf(value).Convert(fieldType).Interface(), nil
+	case fieldType.Kind() == reflect.Array && fieldType.Elem().Kind() == reflect.Uint8 && fieldType.Len() == len(value):
+		rv := reflect.New(fieldType).Elem()
+		reflect.Copy(rv, reflect.ValueOf(value))
+		return rv.Interface(), nil
+	default:
+		return nil, fmt.Errorf("invalid field type %s of %s for generator, should be string or bytes", field.FieldType, field.Name)
+	}
+}
+
+// SnowflakeEpoch default epoch of SnowflakeGenerator, 2020-01-01 00:00:00 UTC
+var SnowflakeEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// SnowflakeGenerator generates 64 bits time-ordered ids composed by 41 bits milliseconds since epoch, 10 bits node
+// id and 12 bits sequence, use different node id for each process, e.g:
+//
+//	generator, err := schema.NewSnowflakeGenerator(nodeID)
+//	schema.RegisterGenerator("snowflake", generator)
+type SnowflakeGenerator struct {
+	Node  int64
+	Epoch time.Time
+
+	mu       sync.Mutex
+	lastTime int64
+	sequence int64
+}
+
+// NewSnowflakeGenerator creates snowflake generator with node id in [0, 1023], returns error for node id out of range,
+// which would be truncated and collide with other nodes
+func NewSnowflakeGenerator(node int64) (*SnowflakeGenerator, error) {
+	if node < 0 || node > 0x3ff {
+		return nil, fmt.Errorf("invalid snowflake node id %d, should be in [0, 1023]", node)
+	}
+	return &SnowflakeGenerator{Node: node, Epoch: SnowflakeEpoch}, nil
+}
+
+// Generate implements generator interface
+func (g *SnowflakeGenerator) Generate(ctx context.Context, field *Field) (interface{}, error) {
+	if g.Node < 0 || g.Node > 0x3ff {
+		return nil, fmt.Errorf("invalid snowflake node id %d, should be in [0, 1023]", g.Node)
+	}
+
+	g.mu.Lock()
+	now := time.Since(g.Epoch).Milliseconds()
+	if now <= g.lastTime {
+		// same millisecond or clock moved backwards, borrow from the next millisecond if sequence exhausted
+		if g.sequence = (g.sequence + 1) & 0xfff; g.sequence == 0 {
+			g.lastTime++
+		}
+	} else {
+		g.lastTime, g.sequence = now, 0
+	}
+	id := g.lastTime<<22 | g.Node<<12 | g.sequence
+	g.mu.Unlock()
+
+	switch field.IndirectFieldType.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return id, nil
+	case reflect.String:
+		return fmt.Sprint(id), nil
+	default:
+		return nil, fmt.Errorf("invalid field type %s of %s for snowflake generator, should be int64, uint64 or string", field.FieldType, field.Name)
+	}
+}
@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Inheritance single table inheritance of a subtype, whose records are stored in the table of its base struct and
+// distinguished by the discriminator column, e.g:
+//
+//	type Vehicle struct {
+//		ID   uint
+//		Type string
+//		Name string
+//	}
+//
+//	type Car struct {
+//		Vehicle `gorm:"inheritance:single;discriminator:type"`
+//		Doors   int
+//	}
+//
+// the discriminator value is the name of the subtype by default, use tag `discriminatorValue:car` to override it
+type Inheritance struct {
+	Base          *Schema
+	Discriminator *Field
+	Value         string
+}
+
+// subtypesKey cache key of the subtypes registered for the base schema
+type subtypesKey struct {
+	modelType reflect.Type
+}
+
+// subtypes subtypes of single table inheritance base schema
+type subtypes struct {
+	discriminator string
+	schemas       sync.Map // discriminator value => *Schema
+}
+
+// parseInheritance parses single table inheritance from the embedded base field, registers the schema as a subtype of the base
+func (schema *Schema) parseInheritance(field *Field) {
+	if kind := strings.ToLower(field.TagSettings["INHERITANCE"]); kind != "single" {
+		schema.err = fmt.Errorf("unsupported inheritance %s for %s, only single table inheritance is supported", kind, schema.Name)
+		return
+	}
+
+	base, err := Parse(reflect.New(field.IndirectFieldType).Interface(), schema.cacheStore, schema.namer)
+	if err != nil {
+		schema.err = err
+		return
+	}
+
+	discriminator := schema.LookUpField(field.TagSettings["DISCRIMINATOR"])
+	if discriminator == nil || base.LookUpField(discriminator.DBName) == nil {
+		schema.err = fmt.Errorf("invalid discriminator %s for %s, should be a field of %s", field.TagSettings["DISCRIMINATOR"], schema.Name, base.Name)
+		return
+	}
+
+	schema.Inheritance = &Inheritance{Base: base, Discriminator: discriminator, Value: schema.Name}
+	if value, ok := field.TagSettings["DISCRIMINATORVALUE"]; ok && value != "" {
+		schema.Inheritance.Value = value
+	}
+
+	// use the table of base unless the table name is specified
+	modelValue := reflect.New(schema.ModelType).Interface()
+	if _, ok := modelValue.(Tabler); !ok {
+		if _, ok := modelValue.(TablerWithNamer); !ok && schema.Table == schema.namer.TableName(schema.ModelType.Name()) {
+			schema.Table = base.Table
+		}
+	}
+
+	v, _ := schema.cacheStore.LoadOrStore(subtypesKey{modelType: base.ModelType}, &subtypes{discriminator: discriminator.DBName})
+	v.(*subtypes).schemas.Store(schema.Inheritance.Value, schema)
+}
+
+// Discriminator returns the discriminator column of single table inheritance, returns empty string if the schema is
+// not a subtype, or a base without any registered subtype
+func (schema *Schema) Discriminator() string {
+	if schema.Inheritance != nil {
+		return schema.Inheritance.Discriminator.DBName
+	}
+
+	if v, ok := schema.cacheStore.Load(subtypesKey{modelType: schema.ModelType}); ok {
+		return v.(*subtypes).discriminator
+	}
+	return ""
+}
+
+// LookUpSubtype returns the registered subtype schema of the discriminator value, returns nil if not registered,
+// subtypes are registered when parsed, use DB.SetupSubtypes to register them explicitly
+func (schema *Schema) LookUpSubtype(value string) *Schema {
+	base := schema
+	if schema.Inheritance != nil {
+		base = schema.Inheritance.Base
+	}
+
+	if v, ok := base.cacheStore.Load(subtypesKey{modelType: base.ModelType}); ok {
+		if s, ok := v.(*subtypes).schemas.Load(value); ok {
+			return s.(*Schema)
+		}
+	}
+	return nil
+}
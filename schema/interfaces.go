@@ -15,6 +15,11 @@ type GormDataTypeInterface interface {
 	GormDataType() string
 }
 
+// EnumInterface field types implementing it are enums with the allowed values, same as tag `enum:"a,b,c"`
+type EnumInterface interface {
+	EnumValues() []string
+}
+
 // FieldNewValuePool field new scan value pool
 type FieldNewValuePool interface {
 	Get() interface{}
@@ -40,3 +45,8 @@ type UpdateClausesInterface interface {
 type DeleteClausesInterface interface {
 	DeleteClauses(*Field) []clause.Interface
 }
+
+// RestoreClausesInterface restore clauses interface
+type RestoreClausesInterface interface {
+	RestoreClauses(*Field) []clause.Interface
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
+	"unicode"
 
 	"github.com/jinzhu/inflection"
 	"gorm.io/gorm/clause"
@@ -14,11 +16,14 @@ import (
 type RelationshipType string
 
 const (
-	HasOne    RelationshipType = "has_one"      // HasOneRel has one relationship
-	HasMany   RelationshipType = "has_many"     // HasManyRel has many relationship
-	BelongsTo RelationshipType = "belongs_to"   // BelongsToRel belongs to relationship
-	Many2Many RelationshipType = "many_to_many" // Many2ManyRel many to many relationship
-	has       RelationshipType = "has"
+	HasOne         RelationshipType = "has_one"          // HasOneRel has one relationship
+	HasMany        RelationshipType = "has_many"         // HasManyRel has many relationship
+	BelongsTo      RelationshipType = "belongs_to"       // BelongsToRel belongs to relationship
+	Many2Many      RelationshipType = "many_to_many"     // Many2ManyRel many to many relationship
+	HasManyThrough RelationshipType = "has_many_through" // HasManyThroughRel has many relationship through an intermediate relationship
+	// PolymorphicBelongsTo belongs to the owner of polymorphic relation, whose model is decided by the polymorphic type
+	PolymorphicBelongsTo RelationshipType = "polymorphic_belongs_to"
+	has                  RelationshipType = "has"
 )
 
 type Relationships struct {
@@ -40,6 +45,10 @@ type Relationship struct {
 	Schema                   *Schema
 	FieldSchema              *Schema
 	JoinTable                *Schema
+	Through                  []*Relationship // relationships to reach the records of has many through relationship, e.g: Country.Users, User.Posts
+	OrderBy                  string          // default order of preloading, in the join table for many2many relation if exists, e.g: `orderBy:position`
+	Positioned               bool            // maintain OrderBy as position with association mode, e.g: `orderBy:position;positioned`
+	polymorphicTypes         *sync.Map       // polymorphic type value => owner schema of polymorphic belongs to relation
 	foreignKeys, primaryKeys []string
 }
 
@@ -66,20 +75,41 @@ func (schema *Schema) parseRelation(field *Field) *Relationship {
 			Schema:      schema,
 			foreignKeys: toColumns(field.TagSettings["FOREIGNKEY"]),
 			primaryKeys: toColumns(field.TagSettings["REFERENCES"]),
+			OrderBy:     field.TagSettings["ORDERBY"],
 		}
 	)
 
+	if _, ok := field.TagSettings["POSITIONED"]; ok && relation.OrderBy != "" {
+		relation.Positioned = true
+	}
+
+	if !isValidOrderBy(relation.OrderBy) {
+		schema.err = fmt.Errorf("invalid orderBy %s for %v on field %s, should be the name of a field or column", relation.OrderBy, schema, field.Name)
+		return nil
+	}
+
 	cacheStore := schema.cacheStore
 
+	// polymorphic belongs to relation has no field schema, the owner model is decided by polymorphic type
+	if field.IndirectFieldType.Kind() == reflect.Interface && hasPolymorphicRelation(field.TagSettings) {
+		if schema.buildPolymorphicBelongsToRelation(relation, field); schema.err == nil {
+			schema.setRelation(relation)
+		}
+		return relation
+	}
+
 	if relation.FieldSchema, err = getOrParse(fieldValue, cacheStore, schema.namer); err != nil {
 		schema.err = err
 		return nil
 	}
 
-	if hasPolymorphicRelation(field.TagSettings) {
-		schema.buildPolymorphicRelation(relation, field)
+	if field.TagSettings["THROUGH"] != "" {
+		// built after all relations parsed, see buildThroughRelation
+		relation.Type = HasManyThrough
 	} else if many2many := field.TagSettings["MANY2MANY"]; many2many != "" {
 		schema.buildMany2ManyRelation(relation, field, many2many)
+	} else if hasPolymorphicRelation(field.TagSettings) {
+		schema.buildPolymorphicRelation(relation, field)
 	} else if belongsTo := field.TagSettings["BELONGSTO"]; belongsTo != "" {
 		schema.guessRelation(relation, field, guessBelongs)
 	} else {
@@ -260,6 +290,64 @@ func (schema *Schema) buildPolymorphicRelation(relation *Relationship, field *Fi
 	relation.Type = has
 }
 
+// Comment belongs to the owner of polymorphic relation `Commentable`, which might be Post or Video decided by CommentableType
+//
+//	type Comment struct {
+//	  CommentableID   int
+//	  CommentableType string
+//	  Commentable     interface{} `gorm:"polymorphic:Commentable;"`
+//	}
+//	type Post struct {
+//	  Comments []Comment `gorm:"polymorphic:Commentable;"`
+//	}
+//
+// owners are not known until registered for their polymorphic type values with DB.SetupPolymorphicType
+func (schema *Schema) buildPolymorphicBelongsToRelation(relation *Relationship, field *Field) {
+	var (
+		polymorphic = field.TagSettings["POLYMORPHIC"]
+		typeName    = polymorphic + "Type"
+		typeId      = polymorphic + "ID"
+	)
+
+	if value, ok := field.TagSettings["POLYMORPHICTYPE"]; ok {
+		typeName = strings.TrimSpace(value)
+	}
+
+	if value, ok := field.TagSettings["POLYMORPHICID"]; ok {
+		typeId = strings.TrimSpace(value)
+	}
+
+	relation.Type = PolymorphicBelongsTo
+	relation.polymorphicTypes = &sync.Map{}
+	relation.Polymorphic = &Polymorphic{
+		PolymorphicType: schema.FieldsByName[typeName],
+		PolymorphicID:   schema.FieldsByName[typeId],
+	}
+
+	if relation.Polymorphic.PolymorphicType == nil {
+		schema.err = fmt.Errorf("invalid polymorphic type for %v on field %s, missing field %s", schema, field.Name, typeName)
+	} else if relation.Polymorphic.PolymorphicID == nil {
+		schema.err = fmt.Errorf("invalid polymorphic type for %v on field %s, missing field %s", schema, field.Name, typeId)
+	}
+}
+
+// SetupPolymorphicType registers owner as the schema of the polymorphic type value for polymorphic belongs to relation
+func (rel *Relationship) SetupPolymorphicType(value string, owner *Schema) {
+	if rel.polymorphicTypes != nil {
+		rel.polymorphicTypes.Store(value, owner)
+	}
+}
+
+// LookUpPolymorphicType returns the owner schema registered for the polymorphic type value of polymorphic belongs to relation
+func (rel *Relationship) LookUpPolymorphicType(value string) *Schema {
+	if rel.polymorphicTypes != nil {
+		if v, ok := rel.polymorphicTypes.Load(value); ok {
+			return v.(*Schema)
+		}
+	}
+	return nil
+}
+
 func (schema *Schema) buildMany2ManyRelation(relation *Relationship, field *Field, many2many string) {
 	relation.Type = Many2Many
 
@@ -288,6 +376,31 @@ func (schema *Schema) buildMany2ManyRelation(relation *Relationship, field *Fiel
 		}
 	}
 
+	// polymorphic many2many relations store the owner's table name in the type column of join table, e.g:
+	// `many2many:taggables;polymorphic:Taggable` => taggables(taggable_id, taggable_type, tag_id)
+	var polymorphicType, polymorphicID string
+	if hasPolymorphicRelation(field.TagSettings) {
+		polymorphic := field.TagSettings["POLYMORPHIC"]
+		polymorphicType, polymorphicID = polymorphic+"Type", polymorphic+"ID"
+		if value, ok := field.TagSettings["POLYMORPHICTYPE"]; ok {
+			polymorphicType = strings.TrimSpace(value)
+		}
+
+		if value, ok := field.TagSettings["POLYMORPHICID"]; ok {
+			polymorphicID = strings.TrimSpace(value)
+		}
+
+		relation.Polymorphic = &Polymorphic{Value: schema.Table}
+		if value, ok := field.TagSettings["POLYMORPHICVALUE"]; ok {
+			relation.Polymorphic.Value = strings.TrimSpace(value)
+		}
+
+		if len(ownForeignFields) != 1 {
+			schema.err = fmt.Errorf("invalid polymorphic foreign keys %+v for %v on field %s", relation.foreignKeys, schema, field.Name)
+			return
+		}
+	}
+
 	if len(relation.primaryKeys) > 0 {
 		refForeignFields = []*Field{}
 		for _, foreignKey := range relation.primaryKeys {
@@ -304,6 +417,8 @@ func (schema *Schema) buildMany2ManyRelation(relation *Relationship, field *Fiel
 		joinFieldName := strings.Title(schema.Name) + ownField.Name
 		if len(joinForeignKeys) > idx {
 			joinFieldName = strings.Title(joinForeignKeys[idx])
+		} else if polymorphicID != "" {
+			joinFieldName = polymorphicID
 		}
 
 		ownFieldsMap[joinFieldName] = ownField
@@ -317,6 +432,14 @@ func (schema *Schema) buildMany2ManyRelation(relation *Relationship, field *Fiel
 		})
 	}
 
+	if polymorphicType != "" {
+		joinTableFields = append(joinTableFields, reflect.StructField{
+			Name: polymorphicType,
+			Type: reflect.TypeOf(""),
+			Tag:  `gorm:"primaryKey;size:255"`,
+		})
+	}
+
 	for idx, relField := range refForeignFields {
 		joinFieldName := strings.Title(relation.FieldSchema.Name) + relField.Name
 
@@ -346,6 +469,15 @@ func (schema *Schema) buildMany2ManyRelation(relation *Relationship, field *Fiel
 		}
 	}
 
+	positionFieldName := "Gorm" + strings.Title(relation.OrderBy)
+	if relation.Positioned {
+		joinTableFields = append(joinTableFields, reflect.StructField{
+			Name: positionFieldName,
+			Type: reflect.TypeOf(0),
+			Tag:  reflect.StructTag(fmt.Sprintf(`gorm:"column:%s"`, schema.namer.ColumnName("", relation.OrderBy))),
+		})
+	}
+
 	joinTableFields = append(joinTableFields, reflect.StructField{
 		Name: strings.Title(schema.Name) + field.Name,
 		Type: schema.ModelType,
@@ -391,6 +523,20 @@ func (schema *Schema) buildMany2ManyRelation(relation *Relationship, field *Fiel
 	// build references
 	for _, f := range relation.JoinTable.Fields {
 		if f.Creatable || f.Readable || f.Updatable {
+			if relation.Positioned && f.Name == positionFieldName {
+				continue
+			}
+
+			if relation.Polymorphic != nil && f.Name == polymorphicType {
+				relation.Polymorphic.PolymorphicType = f
+				relation.JoinTable.PrimaryFields = append(relation.JoinTable.PrimaryFields, f)
+				relation.References = append(relation.References, &Reference{
+					PrimaryValue: relation.Polymorphic.Value,
+					ForeignKey:   f,
+				})
+				continue
+			}
+
 			// use same data type for foreign keys
 			if copyableDataType(fieldsMap[f.Name].DataType) {
 				f.DataType = fieldsMap[f.Name].DataType
@@ -404,10 +550,15 @@ func (schema *Schema) buildMany2ManyRelation(relation *Relationship, field *Fiel
 			if of, ok := ownFieldsMap[f.Name]; ok {
 				joinRel := relation.JoinTable.Relationships.Relations[relName]
 				joinRel.Field = relation.Field
-				joinRel.References = append(joinRel.References, &Reference{
-					PrimaryKey: of,
-					ForeignKey: f,
-				})
+				if relation.Polymorphic != nil {
+					// join table is shared by owners of different types, don't create foreign key constraint for it
+					relation.Polymorphic.PolymorphicID = f
+				} else {
+					joinRel.References = append(joinRel.References, &Reference{
+						PrimaryKey: of,
+						ForeignKey: f,
+					})
+				}
 
 				relation.References = append(relation.References, &Reference{
 					PrimaryKey:    of,
@@ -435,6 +586,41 @@ func (schema *Schema) buildMany2ManyRelation(relation *Relationship, field *Fiel
 	}
 }
 
+// buildThroughRelation builds has many through relationship with the relation of current schema in `through` tag
+// and the has one, has many relation of the intermediate schema, e.g: `Posts []Post gorm:"through:Users"` => Country.Users, User.Posts
+func (schema *Schema) buildThroughRelation(relation *Relationship) {
+	throughName := relation.Field.TagSettings["THROUGH"]
+	through := schema.Relationships.Relations[throughName]
+	if through == nil || (through.Type != HasOne && through.Type != HasMany) {
+		schema.err = fmt.Errorf("invalid through relation %s for %v on field %s, should be has one or has many relation", throughName, schema, relation.Name)
+		return
+	}
+
+	if relation.Field.IndirectFieldType.Kind() != reflect.Slice {
+		schema.err = fmt.Errorf("invalid through relation for %v on field %s, should be slice", schema, relation.Name)
+		return
+	}
+
+	target := through.FieldSchema.Relationships.Relations[relation.Name]
+	if target == nil || target.FieldSchema != relation.FieldSchema || (target.Type != HasOne && target.Type != HasMany) {
+		target = nil
+		for _, rel := range append(through.FieldSchema.Relationships.HasMany, through.FieldSchema.Relationships.HasOne...) {
+			if rel.FieldSchema == relation.FieldSchema {
+				target = rel
+				break
+			}
+		}
+	}
+
+	if target == nil {
+		schema.err = fmt.Errorf("invalid through relation for %v on field %s, missing has one or has many relation of %v in %v",
+			schema, relation.Name, relation.FieldSchema, through.FieldSchema)
+		return
+	}
+
+	relation.Through = []*Relationship{through, target}
+}
+
 type guessLevel int
 
 const (
@@ -644,7 +830,7 @@ func (constraint *Constraint) Build() (sql string, vars []interface{}) {
 
 func (rel *Relationship) ParseConstraint() *Constraint {
 	str := rel.Field.TagSettings["CONSTRAINT"]
-	if str == "-" {
+	if str == "-" || rel.Type == PolymorphicBelongsTo {
 		return nil
 	}
 
@@ -707,6 +893,53 @@ func (rel *Relationship) ParseConstraint() *Constraint {
 	return &constraint
 }
 
+// OrderByField returns the field of OrderBy, which is in the join table for many2many relation if exists
+func (rel *Relationship) OrderByField() *Field {
+	if rel.OrderBy == "" {
+		return nil
+	}
+
+	if rel.JoinTable != nil {
+		if field := rel.JoinTable.LookUpField(rel.OrderBy); field != nil {
+			return field
+		}
+	}
+	return rel.FieldSchema.LookUpField(rel.OrderBy)
+}
+
+// isValidOrderBy checks orderBy is empty or the name of a field or column, e.g: `position`, `sort_order`
+func isValidOrderBy(orderBy string) bool {
+	for idx, c := range orderBy {
+		if c != '_' && !unicode.IsLetter(c) && (idx == 0 || !unicode.IsDigit(c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// PositionField returns the field of OrderBy for positioned relation
+func (rel *Relationship) PositionField() *Field {
+	if !rel.Positioned || (rel.Type != HasMany && rel.Type != Many2Many) {
+		return nil
+	}
+	return rel.OrderByField()
+}
+
+// PivotField returns the field of the related schema to store the join model record of many2many relation,
+// whose type is the join model setup with SetupJoinTable, e.g: `Member ProjectMember gorm:"-"`
+func (rel *Relationship) PivotField() *Field {
+	if rel.JoinTable == nil {
+		return nil
+	}
+
+	for _, field := range rel.FieldSchema.Fields {
+		if field.IndirectFieldType == rel.JoinTable.ModelType {
+			return field
+		}
+	}
+	return nil
+}
+
 func (rel *Relationship) ToQueryConditions(ctx context.Context, reflectValue reflect.Value) (conds []clause.Expression) {
 	table := rel.FieldSchema.Table
 	foreignFields := []*Field{}
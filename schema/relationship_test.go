@@ -305,6 +305,71 @@ func TestMany2ManyOverrideForeignKey(t *testing.T) {
 	})
 }
 
+func TestPolymorphicMany2Many(t *testing.T) {
+	type Tag struct {
+		gorm.Model
+		Name string
+	}
+
+	type Post struct {
+		gorm.Model
+		Tags []Tag `gorm:"many2many:taggables;polymorphic:Taggable"`
+	}
+
+	checkStructRelation(t, &Post{}, Relation{
+		Name: "Tags", Type: schema.Many2Many, Schema: "Post", FieldSchema: "Tag",
+		Polymorphic: Polymorphic{ID: "TaggableID", Type: "TaggableType", Value: "posts"},
+		JoinTable:   JoinTable{Name: "taggables", Table: "taggables"},
+		References: []Reference{
+			{"ID", "Post", "TaggableID", "taggables", "", true},
+			{"", "", "TaggableType", "taggables", "posts", false},
+			{"ID", "Tag", "TagID", "taggables", "", false},
+		},
+	})
+}
+
+func TestHasManyThrough(t *testing.T) {
+	type Post struct {
+		gorm.Model
+		UserID uint
+	}
+
+	type User struct {
+		gorm.Model
+		CountryID uint
+		Posts     []Post
+	}
+
+	type Country struct {
+		gorm.Model
+		Users []User
+		Posts []Post `gorm:"through:Users"`
+	}
+
+	s, err := schema.Parse(&Country{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse country, got error %v", err)
+	}
+
+	rel := s.Relationships.Relations["Posts"]
+	if rel.Type != schema.HasManyThrough || len(rel.Through) != 2 {
+		t.Fatalf("invalid has many through relation, got %#v", rel)
+	}
+
+	if rel.Through[0] != s.Relationships.Relations["Users"] || rel.Through[1].Schema.Name != "User" || rel.Through[1].Name != "Posts" {
+		t.Errorf("invalid through relations, got %v.%v, %v.%v", rel.Through[0].Schema.Name, rel.Through[0].Name, rel.Through[1].Schema.Name, rel.Through[1].Name)
+	}
+
+	type InvalidCountry struct {
+		gorm.Model
+		Posts []Post `gorm:"through:Users"`
+	}
+
+	if _, err := schema.Parse(&InvalidCountry{}, &sync.Map{}, schema.NamingStrategy{}); err == nil {
+		t.Errorf("should return error for missing through relation")
+	}
+}
+
 func TestMany2ManySharedForeignKey(t *testing.T) {
 	type Profile struct {
 		gorm.Model
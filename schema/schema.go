@@ -16,15 +16,17 @@ import (
 type callbackType string
 
 const (
-	callbackTypeBeforeCreate callbackType = "BeforeCreate"
-	callbackTypeBeforeUpdate callbackType = "BeforeUpdate"
-	callbackTypeAfterCreate  callbackType = "AfterCreate"
-	callbackTypeAfterUpdate  callbackType = "AfterUpdate"
-	callbackTypeBeforeSave   callbackType = "BeforeSave"
-	callbackTypeAfterSave    callbackType = "AfterSave"
-	callbackTypeBeforeDelete callbackType = "BeforeDelete"
-	callbackTypeAfterDelete  callbackType = "AfterDelete"
-	callbackTypeAfterFind    callbackType = "AfterFind"
+	callbackTypeBeforeCreate  callbackType = "BeforeCreate"
+	callbackTypeBeforeUpdate  callbackType = "BeforeUpdate"
+	callbackTypeAfterCreate   callbackType = "AfterCreate"
+	callbackTypeAfterUpdate   callbackType = "AfterUpdate"
+	callbackTypeBeforeSave    callbackType = "BeforeSave"
+	callbackTypeAfterSave     callbackType = "AfterSave"
+	callbackTypeBeforeDelete  callbackType = "BeforeDelete"
+	callbackTypeAfterDelete   callbackType = "AfterDelete"
+	callbackTypeBeforeRestore callbackType = "BeforeRestore"
+	callbackTypeAfterRestore  callbackType = "AfterRestore"
+	callbackTypeAfterFind     callbackType = "AfterFind"
 )
 
 // ErrUnsupportedDataType unsupported data type
@@ -48,9 +50,13 @@ type Schema struct {
 	QueryClauses              []clause.Interface
 	UpdateClauses             []clause.Interface
 	DeleteClauses             []clause.Interface
+	RestoreClauses            []clause.Interface
+	Inheritance               *Inheritance
 	BeforeCreate, AfterCreate bool
 	BeforeUpdate, AfterUpdate bool
 	BeforeDelete, AfterDelete bool
+	BeforeRestore             bool
+	AfterRestore              bool
 	BeforeSave, AfterSave     bool
 	AfterFind                 bool
 	err                       error
@@ -199,9 +205,13 @@ func ParseWithSpecialTableName(dest interface{}, cacheStore *sync.Map, namer Nam
 		return s, s.err
 	}
 
+	var inheritanceField *Field
 	for i := 0; i < modelType.NumField(); i++ {
 		if fieldStruct := modelType.Field(i); ast.IsExported(fieldStruct.Name) {
 			if field := schema.ParseField(fieldStruct); field.EmbeddedSchema != nil {
+				if _, ok := field.TagSettings["INHERITANCE"]; ok {
+					inheritanceField = field
+				}
 				schema.Fields = append(schema.Fields, field.EmbeddedSchema.Fields...)
 			} else {
 				schema.Fields = append(schema.Fields, field)
@@ -282,13 +292,27 @@ func ParseWithSpecialTableName(dest interface{}, cacheStore *sync.Map, namer Nam
 		schema.PrimaryFieldDBNames = append(schema.PrimaryFieldDBNames, field.DBName)
 	}
 
+	for _, field := range schema.Fields {
+		if _, ok := field.TagSettings["BLINDINDEX"]; ok {
+			if schema.setupBlindIndex(field); schema.err != nil {
+				return schema, schema.err
+			}
+		}
+	}
+
+	if _, embedded := cacheStore.Load(embeddedCacheKey); inheritanceField != nil && !embedded {
+		if schema.parseInheritance(inheritanceField); schema.err != nil {
+			return schema, schema.err
+		}
+	}
+
 	for _, field := range schema.Fields {
 		if field.DataType != "" && field.HasDefaultValue && field.DefaultValueInterface == nil {
 			schema.FieldsWithDefaultDBValue = append(schema.FieldsWithDefaultDBValue, field)
 		}
 	}
 
-	if field := schema.PrioritizedPrimaryField; field != nil {
+	if field := schema.PrioritizedPrimaryField; field != nil && field.Generator == nil {
 		switch field.GORMDataType {
 		case Int, Uint:
 			if _, ok := field.TagSettings["AUTOINCREMENT"]; !ok {
@@ -307,6 +331,7 @@ func ParseWithSpecialTableName(dest interface{}, cacheStore *sync.Map, namer Nam
 		callbackTypeBeforeUpdate, callbackTypeAfterUpdate,
 		callbackTypeBeforeSave, callbackTypeAfterSave,
 		callbackTypeBeforeDelete, callbackTypeAfterDelete,
+		callbackTypeBeforeRestore, callbackTypeAfterRestore,
 		callbackTypeAfterFind,
 	}
 	for _, cbName := range callbackTypes {
@@ -363,6 +388,18 @@ func ParseWithSpecialTableName(dest interface{}, cacheStore *sync.Map, namer Nam
 			if fc, ok := fieldInterface.(DeleteClausesInterface); ok {
 				field.Schema.DeleteClauses = append(field.Schema.DeleteClauses, fc.DeleteClauses(field)...)
 			}
+
+			if fc, ok := fieldInterface.(RestoreClausesInterface); ok {
+				field.Schema.RestoreClauses = append(field.Schema.RestoreClauses, fc.RestoreClauses(field)...)
+			}
+		}
+
+		for _, field := range schema.Fields {
+			if rel := schema.Relationships.Relations[field.Name]; rel != nil && rel.Field == field && rel.Type == HasManyThrough {
+				if schema.buildThroughRelation(rel); schema.err != nil {
+					return schema, schema.err
+				}
+			}
 		}
 	}
 
@@ -395,6 +432,10 @@ func callBackToMethodValue(modelType reflect.Value, cbType callbackType) reflect
 		return modelType.MethodByName(string(callbackTypeBeforeDelete))
 	case callbackTypeAfterDelete:
 		return modelType.MethodByName(string(callbackTypeAfterDelete))
+	case callbackTypeBeforeRestore:
+		return modelType.MethodByName(string(callbackTypeBeforeRestore))
+	case callbackTypeAfterRestore:
+		return modelType.MethodByName(string(callbackTypeAfterRestore))
 	case callbackTypeAfterFind:
 		return modelType.MethodByName(string(callbackTypeAfterFind))
 	default:
@@ -334,3 +334,39 @@ func TestCompositePrimaryKeyWithAutoIncrement(t *testing.T) {
 		t.Fatalf("PrioritizedPrimaryField of non autoincrement composite key should be nil")
 	}
 }
+
+type InheritanceAnimal struct {
+	ID      uint
+	Species string
+	Name    string
+}
+
+type InheritanceDog struct {
+	InheritanceAnimal `gorm:"inheritance:single;discriminator:species;discriminatorValue:dog"`
+	Breed             string
+}
+
+func TestParseSingleTableInheritance(t *testing.T) {
+	cacheStore := &sync.Map{}
+	dog, err := schema.Parse(&InheritanceDog{}, cacheStore, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse dog, got error %v", err)
+	}
+
+	animal, err := schema.Parse(&InheritanceAnimal{}, cacheStore, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse animal, got error %v", err)
+	}
+
+	if dog.Table != animal.Table || dog.Inheritance == nil || dog.Inheritance.Base != animal {
+		t.Fatalf("dog should be stored in the table of animal, got %v", dog.Table)
+	}
+
+	if dog.Inheritance.Value != "dog" || dog.Discriminator() != "species" || animal.Discriminator() != "species" {
+		t.Errorf("invalid discriminator, got %v %v", dog.Inheritance.Value, animal.Discriminator())
+	}
+
+	if animal.LookUpSubtype("dog") != dog || animal.LookUpSubtype("cat") != nil {
+		t.Errorf("dog should be registered as the subtype of animal")
+	}
+}
@@ -2,12 +2,23 @@ package schema
 
 import (
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
 	"database/sql/driver"
+	"encoding/base64"
 	"encoding/gob"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"strings"
 	"sync"
@@ -21,11 +32,21 @@ func RegisterSerializer(name string, serializer SerializerInterface) {
 	serializerMap.Store(strings.ToLower(name), serializer)
 }
 
-// GetSerializer get serializer
+// GetSerializer get serializer, serializers could be composed with `+` if the outer one implements
+// SerializerWrapperInterface, e.g: `gzip+json`
 func GetSerializer(name string) (serializer SerializerInterface, ok bool) {
 	v, ok := serializerMap.Load(strings.ToLower(name))
 	if ok {
 		serializer, ok = v.(SerializerInterface)
+	} else if idx := strings.Index(name, "+"); idx > 0 {
+		var wrapper SerializerWrapperInterface
+		if v, ok = serializerMap.Load(strings.ToLower(name[:idx])); ok {
+			if wrapper, ok = v.(SerializerWrapperInterface); ok {
+				if serializer, ok = GetSerializer(name[idx+1:]); ok {
+					serializer = wrapper.Wrap(serializer)
+				}
+			}
+		}
 	}
 	return serializer, ok
 }
@@ -34,6 +55,8 @@ func init() {
 	RegisterSerializer("json", JSONSerializer{})
 	RegisterSerializer("unixtime", UnixSecondSerializer{})
 	RegisterSerializer("gob", GobSerializer{})
+	RegisterSerializer("gzip", CompressedSerializer{Algorithm: Gzip, Threshold: DefaultCompressionThreshold})
+	RegisterSerializer("flate", CompressedSerializer{Algorithm: Flate, Threshold: DefaultCompressionThreshold})
 }
 
 // Serializer field value serializer
@@ -69,6 +92,11 @@ type SerializerValuerInterface interface {
 	Value(ctx context.Context, field *Field, dst reflect.Value, fieldValue interface{}) (interface{}, error)
 }
 
+// SerializerWrapperInterface serializer that wraps another serializer
+type SerializerWrapperInterface interface {
+	Wrap(serializer SerializerInterface) SerializerInterface
+}
+
 // JSONSerializer json serializer
 type JSONSerializer struct{}
 
@@ -168,3 +196,406 @@ func (GobSerializer) Value(ctx context.Context, field *Field, dst reflect.Value,
 	err := gob.NewEncoder(buf).Encode(fieldValue)
 	return buf.Bytes(), err
 }
+
+// Keyring encryption keys of EncryptedSerializer, values are always encrypted with the primary key, other keys are
+// kept to decrypt the values encrypted before key rotation
+type Keyring struct {
+	primary  string
+	aeads    map[string]cipher.AEAD
+	indexKey []byte
+}
+
+// NewKeyring creates a keyring with AES keys (16, 24 or 32 bytes) identified by key id, the primary key is used to
+// encrypt new values; indexKey is the HMAC key of blind indexes, it can't be rotated without rebuilding the indexes
+func NewKeyring(primary string, keys map[string][]byte, indexKey []byte) (*Keyring, error) {
+	keyring := &Keyring{primary: primary, aeads: map[string]cipher.AEAD{}, indexKey: indexKey}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid encryption key id %q", id)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key %q: %w", id, err)
+		}
+
+		if keyring.aeads[id], err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := keyring.aeads[primary]; !ok {
+		return nil, fmt.Errorf("primary encryption key %q not found", primary)
+	}
+	return keyring, nil
+}
+
+// Encrypt encrypts plaintext with the primary key, returns `<key id>:<base64 of nonce and ciphertext>`
+func (keyring *Keyring) Encrypt(plaintext []byte) (string, error) {
+	aead := keyring.aeads[keyring.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	return keyring.primary + ":" + base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// Decrypt decrypts ciphertext encrypted by any key of the keyring
+func (keyring *Keyring) Decrypt(ciphertext string) ([]byte, error) {
+	idx := strings.Index(ciphertext, ":")
+	if idx < 0 {
+		return nil, errors.New("invalid encrypted value")
+	}
+
+	aead, ok := keyring.aeads[ciphertext[:idx]]
+	if !ok {
+		return nil, fmt.Errorf("encryption key %q not found", ciphertext[:idx])
+	}
+
+	data, err := base64.StdEncoding.DecodeString(ciphertext[idx+1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid encrypted value: %w", err)
+	}
+
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("invalid encrypted value")
+	}
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+}
+
+// BlindIndex returns the deterministic HMAC-SHA256 of value, which could be used to find records by the blind index
+// column of an encrypted field, e.g:
+//
+//	db.Where("email_index = ?", keyring.BlindIndex("jinzhu@example.org")).First(&user)
+//
+// returns empty string if the value is nil or can't be encoded
+func (keyring *Keyring) BlindIndex(value interface{}) string {
+	result, _ := keyring.blindIndex(value)
+	return result
+}
+
+func (keyring *Keyring) blindIndex(value interface{}) (string, error) {
+	if len(keyring.indexKey) == 0 {
+		return "", errors.New("blind index key not configured")
+	}
+
+	plaintext, err := encodePlaintext(value)
+	if err != nil || plaintext == nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, keyring.indexKey)
+	mac.Write(plaintext)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// encodePlaintext encodes string, []byte as it is, and other values in json, returns nil for nil values
+func encodePlaintext(value interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch {
+	case !rv.IsValid():
+		return nil, nil
+	case rv.Kind() == reflect.String:
+		return []byte(rv.String()), nil
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return rv.Bytes(), nil
+	default:
+		return json.Marshal(rv.Interface())
+	}
+}
+
+// EncryptedSerializer authenticated encryption serializer with AES-GCM, register it with a keyring before use, e.g:
+//
+//	keyring, err := schema.NewKeyring("2024", map[string][]byte{"2023": oldKey, "2024": newKey}, indexKey)
+//	schema.RegisterSerializer("encrypted", schema.EncryptedSerializer{Keyring: keyring})
+//
+//	type User struct {
+//		ID         uint
+//		Email      string `gorm:"serializer:encrypted"`
+//		EmailIndex string `gorm:"serializer:encrypted;blindIndex:Email;index"`
+//	}
+//
+// values are re-encrypted with the primary key when saved again, field with tag `blindIndex` stores the blind index
+// of the referred encrypted field, records could be found by it with `Where(&User{Email: email}, "EmailIndex")`
+type EncryptedSerializer struct {
+	Keyring *Keyring
+}
+
+// Scan implements serializer interface
+func (es EncryptedSerializer) Scan(ctx context.Context, field *Field, dst reflect.Value, dbValue interface{}) (err error) {
+	if field.TagSettings["BLINDINDEX"] != "" {
+		if dbValue == nil {
+			return field.Set(ctx, dst, "")
+		}
+		return field.Set(ctx, dst, dbValue)
+	}
+
+	fieldValue := reflect.New(field.FieldType).Elem()
+	if dbValue != nil {
+		var ciphertext string
+		switch v := dbValue.(type) {
+		case []byte:
+			ciphertext = string(v)
+		case string:
+			ciphertext = v
+		default:
+			return fmt.Errorf("failed to decrypt value: %#v", dbValue)
+		}
+
+		if ciphertext != "" {
+			plaintext, err := es.Keyring.Decrypt(ciphertext)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt field %s: %w", field.Name, err)
+			}
+
+			value := fieldValue
+			if value.Kind() == reflect.Ptr {
+				value.Set(reflect.New(value.Type().Elem()))
+				value = value.Elem()
+			}
+
+			switch {
+			case value.Kind() == reflect.String:
+				value.SetString(string(plaintext))
+			case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+				value.SetBytes(plaintext)
+			default:
+				if err = json.Unmarshal(plaintext, value.Addr().Interface()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue)
+	return
+}
+
+// Value implements serializer interface
+func (es EncryptedSerializer) Value(ctx context.Context, field *Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	if name := field.TagSettings["BLINDINDEX"]; name != "" {
+		source := field.Schema.LookUpField(name)
+		if source == nil {
+			return nil, fmt.Errorf("invalid blind index field %s for %s", name, field.Name)
+		}
+
+		result, err := es.Keyring.blindIndex(source.ReflectValueOf(ctx, dst).Interface())
+		if result == "" || err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	plaintext, err := encodePlaintext(fieldValue)
+	if plaintext == nil || err != nil {
+		return nil, err
+	}
+	return es.Keyring.Encrypt(plaintext)
+}
+
+// setupBlindIndex treats the blind index field as zero if the referred field is zero, so it is updated along with
+// the referred field when updating with struct, updates with map recompute it from BlindIndexes of the referred field
+func (schema *Schema) setupBlindIndex(field *Field) {
+	source := schema.LookUpField(field.TagSettings["BLINDINDEX"])
+	if source == nil || field.Serializer == nil {
+		schema.err = fmt.Errorf("invalid blind index %s for %s, should be a serializer field referring a field of %s", field.TagSettings["BLINDINDEX"], field.Name, schema.Name)
+		return
+	}
+
+	source.BlindIndexes = append(source.BlindIndexes, field)
+	valueOf := field.ValueOf
+	field.ValueOf = func(ctx context.Context, v reflect.Value) (interface{}, bool) {
+		value, _ := valueOf(ctx, v)
+		_, zero := source.ValueOf(ctx, v)
+		return value, zero
+	}
+}
+
+// CompressionAlgorithm compression algorithm of CompressedSerializer
+type CompressionAlgorithm byte
+
+const (
+	Gzip  CompressionAlgorithm = 'g'
+	Flate CompressionAlgorithm = 'f'
+)
+
+// DefaultCompressionThreshold values smaller than it are stored uncompressed by the registered gzip, flate serializers
+const DefaultCompressionThreshold = 512
+
+// compressedHeader prefix of compressed values followed by the algorithm, values without it are read as uncompressed,
+// compressedTextHeader prefix of base64 encoded compressed values stored in non binary columns, e.g: text
+const (
+	compressedHeader     = "\x00gz"
+	compressedTextHeader = "\x01gz"
+)
+
+// CompressedSerializer compresses values in gzip or flate, it could wrap another serializer, e.g: `serializer:gzip+json`,
+// or compress string, []byte fields directly with `serializer:gzip`; values smaller than Threshold are stored as they
+// are, so do the values stored before compression enabled. Fields keep the data type of serializer fields (text), so
+// enabling compression doesn't alter the columns, compressed values of text columns are base64 encoded, use `type:bytes`
+// to store them in binary. register it again to customize, e.g:
+//
+//	schema.RegisterSerializer("gzip", schema.CompressedSerializer{Algorithm: schema.Gzip, Threshold: 4096, Level: gzip.BestCompression})
+type CompressedSerializer struct {
+	Serializer SerializerInterface
+	Algorithm  CompressionAlgorithm
+	Threshold  int
+	Level      int // compression level, use the default compression level if zero
+}
+
+// Wrap implements SerializerWrapperInterface interface
+func (cs CompressedSerializer) Wrap(serializer SerializerInterface) SerializerInterface {
+	cs.Serializer = serializer
+	return cs
+}
+
+// Scan implements serializer interface
+func (cs CompressedSerializer) Scan(ctx context.Context, field *Field, dst reflect.Value, dbValue interface{}) (err error) {
+	var data []byte
+	switch v := dbValue.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	case nil:
+	default:
+		return fmt.Errorf("failed to decompress value: %#v", dbValue)
+	}
+
+	if len(data) > len(compressedTextHeader) && string(data[:len(compressedTextHeader)]) == compressedTextHeader {
+		encoded := data[len(compressedTextHeader)+1:]
+		decoded := make([]byte, base64.StdEncoding.DecodedLen(len(encoded))+len(compressedHeader)+1)
+		n, err := base64.StdEncoding.Decode(decoded[len(compressedHeader)+1:], encoded)
+		if err != nil {
+			return fmt.Errorf("failed to decode compressed field %s: %w", field.Name, err)
+		}
+		copy(decoded, compressedHeader)
+		decoded[len(compressedHeader)] = data[len(compressedTextHeader)]
+		data = decoded[:len(compressedHeader)+1+n]
+	}
+
+	if len(data) > len(compressedHeader) && string(data[:len(compressedHeader)]) == compressedHeader {
+		var reader io.ReadCloser
+		switch CompressionAlgorithm(data[len(compressedHeader)]) {
+		case Gzip:
+			if reader, err = gzip.NewReader(bytes.NewReader(data[len(compressedHeader)+1:])); err != nil {
+				return fmt.Errorf("failed to decompress field %s: %w", field.Name, err)
+			}
+		case Flate:
+			reader = flate.NewReader(bytes.NewReader(data[len(compressedHeader)+1:]))
+		default:
+			return fmt.Errorf("unsupported compression algorithm %q of field %s", data[len(compressedHeader)], field.Name)
+		}
+
+		data, err = io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return fmt.Errorf("failed to decompress field %s: %w", field.Name, err)
+		}
+		dbValue = data
+	}
+
+	if cs.Serializer != nil {
+		return cs.Serializer.Scan(ctx, field, dst, dbValue)
+	}
+
+	fieldValue := reflect.New(field.FieldType).Elem()
+	if dbValue != nil {
+		value := fieldValue
+		if value.Kind() == reflect.Ptr {
+			value.Set(reflect.New(value.Type().Elem()))
+			value = value.Elem()
+		}
+
+		switch {
+		case value.Kind() == reflect.String:
+			value.SetString(string(data))
+		case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+			value.SetBytes(data)
+		default:
+			return fmt.Errorf("invalid field type %s for CompressedSerializer, only string, []byte supported without nested serializer", field.FieldType)
+		}
+	}
+	field.ReflectValueOf(ctx, dst).Set(fieldValue)
+	return
+}
+
+// Value implements serializer interface
+func (cs CompressedSerializer) Value(ctx context.Context, field *Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	var (
+		data  []byte
+		value = fieldValue
+		err   error
+	)
+
+	if cs.Serializer != nil {
+		if value, err = cs.Serializer.Value(ctx, field, dst, fieldValue); err != nil {
+			return nil, err
+		}
+	} else if rv := reflect.ValueOf(fieldValue); rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		value = rv.Elem().Interface()
+	}
+
+	switch rv := reflect.ValueOf(value); {
+	case !rv.IsValid():
+		return nil, nil
+	case rv.Kind() == reflect.String:
+		data = []byte(rv.String())
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		data = rv.Bytes()
+	default:
+		return nil, fmt.Errorf("invalid value %#v for CompressedSerializer, only string, []byte supported", value)
+	}
+
+	if len(data) < cs.Threshold {
+		// keep the bind type of text columns, which might be bound as binary otherwise
+		if field.DataType != Bytes {
+			return string(data), nil
+		}
+		return data, nil
+	}
+
+	level := cs.Level
+	if level == 0 {
+		level = flate.DefaultCompression
+	}
+
+	buf := bytes.NewBufferString(compressedHeader)
+	buf.WriteByte(byte(cs.Algorithm))
+
+	var writer io.WriteCloser
+	switch cs.Algorithm {
+	case Gzip:
+		writer, err = gzip.NewWriterLevel(buf, level)
+	case Flate:
+		writer, err = flate.NewWriter(buf, level)
+	default:
+		err = fmt.Errorf("unsupported compression algorithm %q", cs.Algorithm)
+	}
+
+	if err == nil {
+		if _, err = writer.Write(data); err == nil {
+			err = writer.Close()
+		}
+	}
+
+	if err == nil && field.DataType != Bytes {
+		compressed := buf.Bytes()
+		return compressedTextHeader + string(cs.Algorithm) + base64.StdEncoding.EncodeToString(compressed[len(compressedHeader)+1:]), nil
+	}
+	return buf.Bytes(), err
+}
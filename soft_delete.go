@@ -1,16 +1,22 @@
 package gorm
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/jinzhu/now"
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
 )
 
+// SoftDeleteTimeKey setting key of the deletion time, records soft deleted in cascade share the same deletion time
+const SoftDeleteTimeKey = "gorm:soft_delete_time"
+
 type DeletedAt sql.NullTime
 
 // Scan implements the Scanner interface.
@@ -74,6 +80,11 @@ func (sd SoftDeleteQueryClause) MergeClause(*clause.Clause) {
 }
 
 func (sd SoftDeleteQueryClause) ModifyStatement(stmt *Statement) {
+	addSoftDeleteCondition(stmt, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}, Value: sd.ZeroValue})
+}
+
+// addSoftDeleteCondition add the not deleted condition to the statement's WHERE clause
+func addSoftDeleteCondition(stmt *Statement, cond clause.Expression) {
 	if _, ok := stmt.Clauses["soft_delete_enabled"]; !ok && !stmt.Statement.Unscoped {
 		if c, ok := stmt.Clauses["WHERE"]; ok {
 			if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) >= 1 {
@@ -88,9 +99,7 @@ func (sd SoftDeleteQueryClause) ModifyStatement(stmt *Statement) {
 			}
 		}
 
-		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
-			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}, Value: sd.ZeroValue},
-		}})
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{cond}})
 		stmt.Clauses["soft_delete_enabled"] = clause.Clause{}
 	}
 }
@@ -121,12 +130,13 @@ func (sd SoftDeleteUpdateClause) ModifyStatement(stmt *Statement) {
 }
 
 func (DeletedAt) DeleteClauses(f *schema.Field) []clause.Interface {
-	return []clause.Interface{SoftDeleteDeleteClause{Field: f, ZeroValue: parseZeroValueTag(f)}}
+	return []clause.Interface{SoftDeleteDeleteClause{Field: f, ZeroValue: parseZeroValueTag(f), DeletedByField: parseFieldTag(f, "DELETEDBYFIELD")}}
 }
 
 type SoftDeleteDeleteClause struct {
-	ZeroValue sql.NullString
-	Field     *schema.Field
+	ZeroValue      sql.NullString
+	Field          *schema.Field
+	DeletedByField *schema.Field
 }
 
 func (sd SoftDeleteDeleteClause) Name() string {
@@ -141,30 +151,408 @@ func (sd SoftDeleteDeleteClause) MergeClause(*clause.Clause) {
 
 func (sd SoftDeleteDeleteClause) ModifyStatement(stmt *Statement) {
 	if stmt.SQL.Len() == 0 && !stmt.Statement.Unscoped {
-		curTime := stmt.DB.NowFunc()
-		stmt.AddClause(clause.Set{{Column: clause.Column{Name: sd.Field.DBName}, Value: curTime}})
-		stmt.SetColumn(sd.Field.DBName, curTime, true)
+		curTime := softDeleteTime(stmt)
+		set := clause.Set{{Column: clause.Column{Name: sd.Field.DBName}, Value: curTime}}
+		if sd.DeletedByField != nil {
+			if actor, ok := ActorFromContext(stmt.Context); ok {
+				set = append(set, clause.Assignment{Column: clause.Column{Name: sd.DeletedByField.DBName}, Value: actor})
+			}
+		}
+
+		for _, assignment := range set {
+			stmt.SetColumn(assignment.Column.Name, assignment.Value, true)
+		}
+		buildSoftDeleteUpdate(stmt, set, SoftDeleteQueryClause{Field: sd.Field, ZeroValue: sd.ZeroValue}.ModifyStatement)
+	}
+}
+
+func (DeletedAt) RestoreClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteRestoreClause{Field: f, ZeroValue: parseZeroValueTag(f), DeletedByField: parseFieldTag(f, "DELETEDBYFIELD")}}
+}
+
+type SoftDeleteRestoreClause struct {
+	ZeroValue      sql.NullString
+	Field          *schema.Field
+	DeletedByField *schema.Field
+}
+
+func (sd SoftDeleteRestoreClause) Name() string {
+	return ""
+}
+
+func (sd SoftDeleteRestoreClause) Build(clause.Builder) {
+}
+
+func (sd SoftDeleteRestoreClause) MergeClause(*clause.Clause) {
+}
+
+func (sd SoftDeleteRestoreClause) ModifyStatement(stmt *Statement) {
+	if stmt.SQL.Len() == 0 {
+		set := clause.Set{{Column: clause.Column{Name: sd.Field.DBName}, Value: sd.ZeroValue}}
+		stmt.SetColumn(sd.Field.DBName, nil, true)
+		if sd.DeletedByField != nil {
+			set = append(set, restoreAssignment(stmt, sd.DeletedByField))
+		}
+
+		buildSoftDeleteUpdate(stmt, set, func(stmt *Statement) {
+			addRestoreCondition(stmt, clause.Neq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}, Value: sd.ZeroValue})
+		})
+	}
+}
+
+// DeletedAt returns the deletion time of the record, implements RestoreClauseInterface
+func (sd SoftDeleteRestoreClause) DeletedAt(ctx context.Context, reflectValue reflect.Value) (time.Time, bool) {
+	if v, ok := sd.Field.ReflectValueOf(ctx, reflectValue).Interface().(DeletedAt); ok && v.Valid {
+		return v.Time, true
+	}
+	return time.Time{}, false
+}
+
+// DeletedAtCondition returns the condition of records deleted at deletedAt, implements RestoreClauseInterface
+func (sd SoftDeleteRestoreClause) DeletedAtCondition(deletedAt time.Time) clause.Expression {
+	column := clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}
+	if deletedAt.IsZero() {
+		return clause.Neq{Column: column, Value: sd.ZeroValue}
+	}
+	return clause.Eq{Column: column, Value: deletedAt}
+}
+
+// RestoreClauseInterface restore clauses implementing RestoreClauseInterface are used to restore associations
+// soft deleted together with their owner
+type RestoreClauseInterface interface {
+	clause.Interface
+	// DeletedAt returns the deletion time of the record
+	DeletedAt(ctx context.Context, reflectValue reflect.Value) (time.Time, bool)
+	// DeletedAtCondition returns the condition of records deleted at deletedAt, or all deleted records if it is zero
+	DeletedAtCondition(deletedAt time.Time) clause.Expression
+}
+
+// addRestoreCondition add the deleted condition to the statement's WHERE clause
+func addRestoreCondition(stmt *Statement, cond clause.Expression) {
+	if _, ok := stmt.Clauses["soft_delete_enabled"]; !ok {
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{cond}})
+		stmt.Clauses["soft_delete_enabled"] = clause.Clause{}
+	}
+}
+
+// restoreAssignment returns the assignment resetting field to its zero value
+func restoreAssignment(stmt *Statement, field *schema.Field) clause.Assignment {
+	stmt.SetColumn(field.DBName, nil, true)
+	if field.FieldType.Kind() == reflect.Ptr {
+		return clause.Assignment{Column: clause.Column{Name: field.DBName}, Value: nil}
+	}
+	return clause.Assignment{Column: clause.Column{Name: field.DBName}, Value: reflect.Zero(field.FieldType).Interface()}
+}
 
-		if stmt.Schema != nil {
-			_, queryValues := schema.GetIdentityFieldValuesMap(stmt.Context, stmt.ReflectValue, stmt.Schema.PrimaryFields)
-			column, values := schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, queryValues)
+// buildSoftDeleteUpdate build update statement of soft delete fields with assignments set, addConditions is used to
+// filter the records to update
+func buildSoftDeleteUpdate(stmt *Statement, set clause.Set, addConditions func(*Statement)) {
+	stmt.AddClause(set)
+
+	if stmt.Schema != nil {
+		_, queryValues := schema.GetIdentityFieldValuesMap(stmt.Context, stmt.ReflectValue, stmt.Schema.PrimaryFields)
+		column, values := schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, queryValues)
+
+		if len(values) > 0 {
+			stmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
+		}
+
+		if stmt.ReflectValue.CanAddr() && stmt.Dest != stmt.Model && stmt.Model != nil {
+			_, queryValues = schema.GetIdentityFieldValuesMap(stmt.Context, reflect.ValueOf(stmt.Model), stmt.Schema.PrimaryFields)
+			column, values = schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, queryValues)
 
 			if len(values) > 0 {
 				stmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
 			}
+		}
+	}
 
-			if stmt.ReflectValue.CanAddr() && stmt.Dest != stmt.Model && stmt.Model != nil {
-				_, queryValues = schema.GetIdentityFieldValuesMap(stmt.Context, reflect.ValueOf(stmt.Model), stmt.Schema.PrimaryFields)
-				column, values = schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, queryValues)
+	addConditions(stmt)
+	stmt.AddClauseIfNotExists(clause.Update{})
+	stmt.Build(stmt.DB.Callback().Update().Clauses...)
+}
 
-				if len(values) > 0 {
-					stmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
-				}
-			}
+// softDeleteTime returns the deletion time of current statement
+func softDeleteTime(stmt *Statement) time.Time {
+	if v, ok := stmt.Settings.Load(SoftDeleteTimeKey); ok {
+		if t, ok := v.(time.Time); ok {
+			return t
 		}
+	}
+	return stmt.DB.NowFunc()
+}
 
-		SoftDeleteQueryClause(sd).ModifyStatement(stmt)
-		stmt.AddClauseIfNotExists(clause.Update{})
-		stmt.Build(stmt.DB.Callback().Update().Clauses...)
+// parseFieldTag returns the schema field referenced by the tag setting of f
+func parseFieldTag(f *schema.Field, name string) *schema.Field {
+	if v, ok := f.TagSettings[name]; ok && v != "" {
+		return f.Schema.LookUpField(v)
+	}
+	return nil
+}
+
+// DeletedAtUnix soft delete field which stores the deletion time as unix timestamp, 0 means not deleted.
+// The precision is second by default, use tag `softDelete:milli` or `softDelete:nano` for others
+//
+//	type User struct {
+//	  ID        uint
+//	  DeletedAt gorm.DeletedAtUnix `gorm:"softDelete:milli"`
+//	}
+type DeletedAtUnix int64
+
+func (DeletedAtUnix) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{softDeleteQueryClause{newSoftDeleteStrategy(f, int64(0))}}
+}
+
+func (DeletedAtUnix) UpdateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{softDeleteUpdateClause{newSoftDeleteStrategy(f, int64(0))}}
+}
+
+func (DeletedAtUnix) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{softDeleteDeleteClause{newSoftDeleteStrategy(f, int64(0))}}
+}
+
+func (DeletedAtUnix) RestoreClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{softDeleteRestoreClause{newSoftDeleteStrategy(f, int64(0))}}
+}
+
+// DeletedFlag soft delete boolean flag, true means deleted. Use tag `deletedAtField` to record the
+// deletion time into another field as well (mixed mode)
+//
+//	type User struct {
+//	  ID        uint
+//	  IsDeleted gorm.DeletedFlag `gorm:"deletedAtField:DeletedAt;deletedByField:DeletedBy"`
+//	  DeletedAt *time.Time
+//	  DeletedBy string
+//	}
+type DeletedFlag bool
+
+// Scan implements the Scanner interface.
+func (n *DeletedFlag) Scan(value interface{}) error {
+	var flag sql.NullBool
+	if err := flag.Scan(value); err != nil {
+		return err
+	}
+	*n = DeletedFlag(flag.Bool)
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (n DeletedFlag) Value() (driver.Value, error) {
+	return bool(n), nil
+}
+
+func (DeletedFlag) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{softDeleteQueryClause{newSoftDeleteStrategy(f, false)}}
+}
+
+func (DeletedFlag) UpdateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{softDeleteUpdateClause{newSoftDeleteStrategy(f, false)}}
+}
+
+func (DeletedFlag) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{softDeleteDeleteClause{newSoftDeleteStrategy(f, false)}}
+}
+
+func (DeletedFlag) RestoreClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{softDeleteRestoreClause{newSoftDeleteStrategy(f, false)}}
+}
+
+// softDeleteStrategy soft delete settings of DeletedAtUnix, DeletedFlag fields
+type softDeleteStrategy struct {
+	Field          *schema.Field
+	ZeroValue      interface{}
+	DeletedAtField *schema.Field
+	DeletedByField *schema.Field
+}
+
+func newSoftDeleteStrategy(f *schema.Field, zeroValue interface{}) softDeleteStrategy {
+	return softDeleteStrategy{
+		Field:          f,
+		ZeroValue:      zeroValue,
+		DeletedAtField: parseFieldTag(f, "DELETEDATFIELD"),
+		DeletedByField: parseFieldTag(f, "DELETEDBYFIELD"),
+	}
+}
+
+// deletedValue returns the value of field for records deleted at curTime
+func (sd softDeleteStrategy) deletedValue(field *schema.Field, curTime time.Time) interface{} {
+	switch field.GORMDataType {
+	case schema.Bool:
+		return true
+	case schema.Int, schema.Uint:
+		switch strings.ToUpper(field.TagSettings["SOFTDELETE"]) {
+		case "NANO":
+			return curTime.UnixNano()
+		case "MILLI":
+			return curTime.UnixNano() / 1e6
+		default:
+			return curTime.Unix()
+		}
+	}
+	return curTime
+}
+
+func (sd softDeleteStrategy) condition() clause.Expression {
+	return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}, Value: sd.ZeroValue}
+}
+
+func (sd softDeleteStrategy) assignments(stmt *Statement) clause.Set {
+	curTime := softDeleteTime(stmt)
+	set := clause.Set{{Column: clause.Column{Name: sd.Field.DBName}, Value: sd.deletedValue(sd.Field, curTime)}}
+
+	if sd.DeletedAtField != nil {
+		set = append(set, clause.Assignment{Column: clause.Column{Name: sd.DeletedAtField.DBName}, Value: sd.deletedValue(sd.DeletedAtField, curTime)})
+	}
+
+	if sd.DeletedByField != nil {
+		if actor, ok := ActorFromContext(stmt.Context); ok {
+			set = append(set, clause.Assignment{Column: clause.Column{Name: sd.DeletedByField.DBName}, Value: actor})
+		}
+	}
+	return set
+}
+
+type softDeleteQueryClause struct {
+	softDeleteStrategy
+}
+
+func (sd softDeleteQueryClause) Name() string {
+	return ""
+}
+
+func (sd softDeleteQueryClause) Build(clause.Builder) {
+}
+
+func (sd softDeleteQueryClause) MergeClause(*clause.Clause) {
+}
+
+func (sd softDeleteQueryClause) ModifyStatement(stmt *Statement) {
+	addSoftDeleteCondition(stmt, sd.condition())
+}
+
+type softDeleteUpdateClause struct {
+	softDeleteStrategy
+}
+
+func (sd softDeleteUpdateClause) Name() string {
+	return ""
+}
+
+func (sd softDeleteUpdateClause) Build(clause.Builder) {
+}
+
+func (sd softDeleteUpdateClause) MergeClause(*clause.Clause) {
+}
+
+func (sd softDeleteUpdateClause) ModifyStatement(stmt *Statement) {
+	if stmt.SQL.Len() == 0 && !stmt.Statement.Unscoped {
+		softDeleteQueryClause(sd).ModifyStatement(stmt)
+	}
+}
+
+type softDeleteDeleteClause struct {
+	softDeleteStrategy
+}
+
+func (sd softDeleteDeleteClause) Name() string {
+	return ""
+}
+
+func (sd softDeleteDeleteClause) Build(clause.Builder) {
+}
+
+func (sd softDeleteDeleteClause) MergeClause(*clause.Clause) {
+}
+
+func (sd softDeleteDeleteClause) ModifyStatement(stmt *Statement) {
+	if stmt.SQL.Len() == 0 && !stmt.Statement.Unscoped {
+		set := sd.assignments(stmt)
+		for _, assignment := range set {
+			stmt.SetColumn(assignment.Column.Name, assignment.Value, true)
+		}
+		buildSoftDeleteUpdate(stmt, set, softDeleteQueryClause(sd).ModifyStatement)
+	}
+}
+
+type softDeleteRestoreClause struct {
+	softDeleteStrategy
+}
+
+func (sd softDeleteRestoreClause) Name() string {
+	return ""
+}
+
+func (sd softDeleteRestoreClause) Build(clause.Builder) {
+}
+
+func (sd softDeleteRestoreClause) MergeClause(*clause.Clause) {
+}
+
+func (sd softDeleteRestoreClause) ModifyStatement(stmt *Statement) {
+	if stmt.SQL.Len() == 0 {
+		set := clause.Set{restoreAssignment(stmt, sd.Field)}
+		if sd.DeletedAtField != nil {
+			set = append(set, restoreAssignment(stmt, sd.DeletedAtField))
+		}
+		if sd.DeletedByField != nil {
+			set = append(set, restoreAssignment(stmt, sd.DeletedByField))
+		}
+
+		buildSoftDeleteUpdate(stmt, set, func(stmt *Statement) {
+			addRestoreCondition(stmt, clause.Neq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}, Value: sd.ZeroValue})
+		})
+	}
+}
+
+// DeletedAt returns the deletion time of the record, implements RestoreClauseInterface
+func (sd softDeleteRestoreClause) DeletedAt(ctx context.Context, reflectValue reflect.Value) (time.Time, bool) {
+	field := sd.Field
+	if sd.DeletedAtField != nil {
+		field = sd.DeletedAtField
+	}
+
+	switch v := reflect.Indirect(field.ReflectValueOf(ctx, reflectValue)); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return sd.timeOf(field, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return sd.timeOf(field, int64(v.Uint()))
+	case reflect.Struct:
+		if t, ok := v.Interface().(time.Time); ok && !t.IsZero() {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (sd softDeleteRestoreClause) timeOf(field *schema.Field, value int64) (time.Time, bool) {
+	if value == 0 {
+		return time.Time{}, false
+	}
+
+	switch strings.ToUpper(field.TagSettings["SOFTDELETE"]) {
+	case "NANO":
+		return time.Unix(0, value), true
+	case "MILLI":
+		return time.Unix(0, value*1e6), true
+	default:
+		return time.Unix(value, 0), true
+	}
+}
+
+// DeletedAtCondition returns the condition of records deleted at deletedAt, implements RestoreClauseInterface
+func (sd softDeleteRestoreClause) DeletedAtCondition(deletedAt time.Time) clause.Expression {
+	deleted := clause.Neq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}, Value: sd.ZeroValue}
+	if deletedAt.IsZero() {
+		return deleted
+	}
+
+	if sd.DeletedAtField != nil {
+		return clause.And(deleted, clause.Eq{
+			Column: clause.Column{Table: clause.CurrentTable, Name: sd.DeletedAtField.DBName},
+			Value:  sd.deletedValue(sd.DeletedAtField, deletedAt),
+		})
+	} else if sd.Field.GORMDataType == schema.Bool {
+		return deleted
 	}
+	return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}, Value: sd.deletedValue(sd.Field, deletedAt)}
 }
@@ -33,6 +33,7 @@ type Statement struct {
 	Selects              []string // selected columns
 	Omits                []string // omit columns
 	Joins                []join
+	Aggregates           []aggregate
 	Preloads             map[string][]interface{}
 	Settings             sync.Map
 	ConnPool             ConnPool
@@ -57,6 +58,13 @@ type join struct {
 	JoinType clause.JoinType
 }
 
+type aggregate struct {
+	Name     string
+	Function string
+	Column   string
+	Conds    []interface{}
+}
+
 // StatementModifier statement modifier interface
 type StatementModifier interface {
 	ModifyStatement(*Statement)
@@ -540,6 +548,11 @@ func (stmt *Statement) clone() *Statement {
 		copy(newStmt.Joins, stmt.Joins)
 	}
 
+	if len(stmt.Aggregates) > 0 {
+		newStmt.Aggregates = make([]aggregate, len(stmt.Aggregates))
+		copy(newStmt.Aggregates, stmt.Aggregates)
+	}
+
 	if len(stmt.scopes) > 0 {
 		newStmt.scopes = make([]func(*DB) *DB, len(stmt.scopes))
 		copy(newStmt.scopes, stmt.scopes)
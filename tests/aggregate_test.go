@@ -0,0 +1,132 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type AggregateCustomer struct {
+	ID              uint
+	Name            string
+	Orders          []AggregateOrder
+	Tags            []AggregateTag `gorm:"many2many:aggregate_customer_tags"`
+	OrdersCount     int            `gorm:"-:migration;->"`
+	OrdersSumAmount int            `gorm:"-:migration;->"`
+	OrdersMaxAmount int            `gorm:"-:migration;->"`
+	TagsCount       int            `gorm:"-:migration;->"`
+}
+
+type AggregateOrder struct {
+	gorm.Model
+	AggregateCustomerID uint
+	Amount              int
+	State               string
+}
+
+type AggregateTag struct {
+	ID   uint
+	Name string
+}
+
+func TestWithAggregate(t *testing.T) {
+	DB.Migrator().DropTable(&AggregateCustomer{}, &AggregateOrder{}, &AggregateTag{}, "aggregate_customer_tags")
+	if err := DB.AutoMigrate(&AggregateCustomer{}, &AggregateOrder{}, &AggregateTag{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	customers := []AggregateCustomer{
+		{
+			Name:   "aggregate_1",
+			Orders: []AggregateOrder{{Amount: 10, State: "paid"}, {Amount: 20, State: "paid"}, {Amount: 30, State: "cancelled"}},
+			Tags:   []AggregateTag{{Name: "tag_1"}, {Name: "tag_2"}},
+		},
+		{Name: "aggregate_2"},
+	}
+	if err := DB.Create(&customers).Error; err != nil {
+		t.Fatalf("failed to create customers, got error %v", err)
+	}
+	DB.Delete(&customers[0].Orders[0])
+
+	var results []AggregateCustomer
+	if err := DB.WithCount("Orders").WithAggregate("Orders", "sum", "amount").WithAggregate("Orders", "MAX", "Amount").
+		WithCount("Tags").Order("id").Find(&results).Error; err != nil {
+		t.Fatalf("failed to find customers, got error %v", err)
+	}
+
+	if len(results) != 2 || results[0].Name != "aggregate_1" {
+		t.Fatalf("failed to find customers, got %#v", results)
+	}
+
+	if results[0].OrdersCount != 2 || results[0].OrdersSumAmount != 50 || results[0].OrdersMaxAmount != 30 || results[0].TagsCount != 2 {
+		t.Errorf("invalid aggregates, got %#v", results[0])
+	}
+
+	if results[1].OrdersCount != 0 || results[1].OrdersSumAmount != 0 || results[1].TagsCount != 0 {
+		t.Errorf("invalid aggregates for customer without associations, got %#v", results[1])
+	}
+
+	var result AggregateCustomer
+	if err := DB.Select("id").WithCount("Orders", "state = ?", "paid").Joins("LEFT JOIN aggregate_customer_tags ON aggregate_customer_tags.aggregate_customer_id = aggregate_customers.id").
+		Where("aggregate_customers.id = ?", customers[0].ID).Take(&result).Error; err != nil {
+		t.Fatalf("failed to find customer, got error %v", err)
+	}
+
+	if result.ID != customers[0].ID || result.Name != "" || result.OrdersCount != 1 {
+		t.Errorf("invalid aggregates with conditions, got %#v", result)
+	}
+
+	if err := DB.WithCount("Tags", func(db *gorm.DB) *gorm.DB {
+		return db.Where("Tags.name = ?", "tag_1")
+	}).First(&result, customers[0].ID).Error; err != nil || result.TagsCount != 1 || result.Name != "aggregate_1" {
+		t.Errorf("invalid aggregates with conditions, got %#v, %v", result, err)
+	}
+
+	var count int64
+	if err := DB.Model(&AggregateCustomer{}).WithCount("Orders").Count(&count).Error; err != nil || count != 2 {
+		t.Errorf("aggregates should be ignored when counting, got %v, %v", count, err)
+	}
+
+	if err := DB.WithAggregate("Orders", "sleep", "amount").Find(&results).Error; !errors.Is(err, gorm.ErrUnsupportedAggregate) {
+		t.Errorf("should return unsupported aggregate error, got %v", err)
+	}
+
+	if err := DB.WithAggregate("Orders", "sum", "unknown").Find(&results).Error; !errors.Is(err, gorm.ErrInvalidField) {
+		t.Errorf("should return invalid field error, got %v", err)
+	}
+}
+
+type AggregateCategory struct {
+	ID            uint
+	Name          string
+	ParentID      *uint
+	Children      []AggregateCategory `gorm:"foreignKey:ParentID"`
+	ChildrenCount int                 `gorm:"-:migration;->"`
+}
+
+func TestWithCountSelfReferential(t *testing.T) {
+	DB.Migrator().DropTable(&AggregateCategory{})
+	if err := DB.AutoMigrate(&AggregateCategory{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	root := AggregateCategory{Name: "root", Children: []AggregateCategory{{Name: "child_1"}, {Name: "child_2"}}}
+	if err := DB.Create(&root).Error; err != nil {
+		t.Fatalf("failed to create categories, got %v", err)
+	}
+
+	var categories []AggregateCategory
+	if err := DB.WithCount("Children").Order("id").Find(&categories).Error; err != nil {
+		t.Fatalf("failed to count children, got %v", err)
+	}
+
+	if len(categories) != 3 || categories[0].ChildrenCount != 2 || categories[1].ChildrenCount != 0 || categories[2].ChildrenCount != 0 {
+		t.Errorf("invalid children count, got %#v", categories)
+	}
+
+	var category AggregateCategory
+	if err := DB.Table("aggregate_categories AS c").WithCount("Children", "Children.name = ?", "child_1").First(&category, "c.id = ?", root.ID).Error; err != nil || category.ChildrenCount != 1 {
+		t.Errorf("invalid children count with aliased table, got %#v, %v", category, err)
+	}
+}
@@ -1,6 +1,7 @@
 package tests_test
 
 import (
+	"errors"
 	"testing"
 
 	"gorm.io/gorm"
@@ -554,3 +555,58 @@ func TestHasManyAssociationUnscoped(t *testing.T) {
 		t.Errorf("expected %d contents, got %d", 0, len(contents))
 	}
 }
+
+func TestHasManySync(t *testing.T) {
+	user := *GetUser("hasmany-sync", Config{Pets: 3})
+
+	if err := DB.Create(&user).Error; err != nil {
+		t.Fatalf("errors happened when create: %v", err)
+	}
+
+	pet := Pet{Name: "pet-has-many-sync"}
+	detached := user.Pets[0].ID
+	result, err := DB.Model(&user).Association("Pets").Sync(user.Pets[1], user.Pets[2], &pet)
+	if err != nil {
+		t.Fatalf("Error happened when sync pets, got %v", err)
+	}
+
+	if pet.ID == 0 || len(result.Attached) != 1 || result.Attached[0] != pet.ID {
+		t.Errorf("invalid attached keys, got %v", result.Attached)
+	}
+
+	if len(result.Detached) != 1 || result.Detached[0] != detached {
+		t.Errorf("invalid detached keys, got %v", result.Detached)
+	}
+
+	if len(user.Pets) != 3 || user.Pets[2] != &pet {
+		t.Errorf("pets should be synced in memory, got %v", user.Pets)
+	}
+
+	AssertAssociationCount(t, user, "Pets", 3, "AfterSync")
+
+	var detachedPet Pet
+	if DB.First(&detachedPet, detached); detachedPet.UserID != nil {
+		t.Errorf("detached pet's foreign key should be cleared, got %v", *detachedPet.UserID)
+	}
+
+	errFailedPet := errors.New("failed to create pet")
+	DB.Callback().Create().Before("gorm:create").Register("test:fail_pet", func(db *gorm.DB) {
+		if db.Statement.Table == "pets" {
+			db.AddError(errFailedPet)
+		}
+	})
+	defer DB.Callback().Create().Remove("test:fail_pet")
+
+	pets := user.Pets
+	result, err = DB.Model(&user).Association("Pets").Sync(&Pet{Name: "pet-has-many-sync-failed"})
+	if !errors.Is(err, errFailedPet) || len(result.Attached) != 0 || len(result.Detached) != 0 {
+		t.Fatalf("should return error when failed to attach, got %v, %+v", err, result)
+	}
+
+	if len(user.Pets) != len(pets) || user.Pets[0] != pets[0] {
+		t.Errorf("pets should be kept in memory when failed to sync, got %v", user.Pets)
+	}
+
+	// the detached pets are rolled back
+	AssertAssociationCount(t, user, "Pets", 3, "AfterFailedSync")
+}
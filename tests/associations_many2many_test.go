@@ -1,6 +1,7 @@
 package tests_test
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -423,3 +424,63 @@ func TestMany2ManyDuplicateBelongsToAssociation(t *testing.T) {
 	AssertEqual(t, nil, err)
 	AssertEqual(t, user2, findUser2)
 }
+
+func TestMany2ManySync(t *testing.T) {
+	user := *GetUser("many2many-sync", Config{Languages: 3})
+
+	if err := DB.Create(&user).Error; err != nil {
+		t.Fatalf("errors happened when create: %v", err)
+	}
+
+	language := Language{Code: "language-many2many-sync", Name: "language-many2many-sync"}
+	detached := user.Languages[1].Code
+	result, err := DB.Model(&user).Association("Languages").Sync(user.Languages[0], &user.Languages[2], &language)
+	if err != nil {
+		t.Fatalf("Error happened when sync languages, got %v", err)
+	}
+
+	if len(result.Attached) != 1 || result.Attached[0] != language.Code {
+		t.Errorf("invalid attached keys, got %v", result.Attached)
+	}
+
+	if len(result.Detached) != 1 || result.Detached[0] != detached {
+		t.Errorf("invalid detached keys, got %v", result.Detached)
+	}
+
+	if len(user.Languages) != 3 || user.Languages[2].Code != language.Code {
+		t.Errorf("languages should be synced in memory, got %v", user.Languages)
+	}
+
+	AssertAssociationCount(t, user, "Languages", 3, "AfterSync")
+
+	var languages []Language
+	DB.Model(&user).Association("Languages").Find(&languages)
+	for _, l := range languages {
+		if l.Code == detached {
+			t.Errorf("detached language should be removed, got %v", languages)
+		}
+	}
+
+	result, err = DB.Model(&user).Association("Languages").Sync(languages)
+	if err != nil || len(result.Attached) != 0 || len(result.Detached) != 0 {
+		t.Errorf("nothing should be changed, got %#v, %v", result, err)
+	}
+
+	result, err = DB.Model(&user).Association("Languages").Sync()
+	if err != nil || len(result.Detached) != 3 {
+		t.Errorf("all languages should be detached, got %#v, %v", result, err)
+	}
+
+	AssertAssociationCount(t, user, "Languages", 0, "AfterSyncEmpty")
+
+	result, err = DB.Model(&user).Association("Languages").Sync(&language, language)
+	if err != nil || len(result.Attached) != 1 || len(user.Languages) != 1 {
+		t.Errorf("duplicated languages should be attached once, got %#v, %v, %v", result, user.Languages, err)
+	}
+
+	AssertAssociationCount(t, user, "Languages", 1, "AfterSyncDuplicated")
+
+	if _, err := DB.Model(&[]User{user}).Association("Languages").Sync(&language); !errors.Is(err, gorm.ErrInvalidValue) {
+		t.Errorf("should return error when sync slice, got %v", err)
+	}
+}
@@ -0,0 +1,309 @@
+package tests_test
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type PositionPlaylist struct {
+	gorm.Model
+	Name   string
+	Tracks []PositionTrack `gorm:"orderBy:position;positioned"`
+	Tags   []PositionTag   `gorm:"many2many:position_playlist_tags;orderBy:position;positioned"`
+}
+
+type PositionTrack struct {
+	gorm.Model
+	Name               string
+	Position           int
+	PositionPlaylistID uint
+}
+
+type PositionTag struct {
+	gorm.Model
+	Name string
+}
+
+func positionNames(values interface{}) (names []string) {
+	switch vs := values.(type) {
+	case []PositionTrack:
+		for _, v := range vs {
+			names = append(names, v.Name)
+		}
+	case []PositionTag:
+		for _, v := range vs {
+			names = append(names, v.Name)
+		}
+	case []PositionMember:
+		for _, v := range vs {
+			names = append(names, v.Name)
+		}
+	}
+	return names
+}
+
+func assertPositionNames(t *testing.T, values interface{}, expects ...string) {
+	t.Helper()
+	names := positionNames(values)
+	if len(names) != len(expects) {
+		t.Fatalf("expects %v, got %v", expects, names)
+	}
+
+	for idx := range names {
+		if names[idx] != expects[idx] {
+			t.Fatalf("expects %v, got %v", expects, names)
+		}
+	}
+}
+
+func TestPositionedHasMany(t *testing.T) {
+	DB.Migrator().DropTable(&PositionPlaylist{}, &PositionTrack{}, "position_playlist_tags", &PositionTag{})
+	if err := DB.AutoMigrate(&PositionPlaylist{}, &PositionTrack{}, &PositionTag{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	playlist := PositionPlaylist{Name: "playlist", Tracks: []PositionTrack{{Name: "a"}, {Name: "b"}}}
+	DB.Create(&playlist)
+
+	if err := DB.Model(&playlist).Association("Tracks").Append(&PositionTrack{Name: "c"}, &PositionTrack{Name: "d"}); err != nil {
+		t.Fatalf("failed to append tracks, got %v", err)
+	}
+
+	var tracks []PositionTrack
+	DB.Order("name").Find(&tracks, "position_playlist_id = ?", playlist.ID)
+	for idx, track := range tracks {
+		if track.Position != idx {
+			t.Errorf("track %v should have position %v, got %v", track.Name, idx, track.Position)
+		}
+	}
+
+	var result PositionPlaylist
+	DB.Preload("Tracks").First(&result, playlist.ID)
+	if len(result.Tracks) != 4 || result.Tracks[2].Name != "c" || result.Tracks[3].Name != "d" {
+		t.Fatalf("appended tracks should be ordered at the end, got %v", positionNames(result.Tracks))
+	}
+
+	if err := DB.Model(&result).Association("Tracks").Move(result.Tracks[3], 0); err != nil {
+		t.Fatalf("failed to move track, got %v", err)
+	}
+	assertPositionNames(t, result.Tracks, "d", "a", "b", "c")
+	for idx, track := range result.Tracks {
+		AssertEqual(t, track.Position, idx)
+	}
+
+	var moved PositionPlaylist
+	DB.Preload("Tracks").First(&moved, playlist.ID)
+	assertPositionNames(t, moved.Tracks, "d", "a", "b", "c")
+
+	if err := DB.Model(&moved).Association("Tracks").Move(moved.Tracks[0], 10); err != nil {
+		t.Fatalf("failed to move track, got %v", err)
+	}
+	DB.Preload("Tracks").First(&moved, playlist.ID)
+	assertPositionNames(t, moved.Tracks, "a", "b", "c", "d")
+
+	if err := DB.Model(&moved).Association("Tracks").Move(&PositionTrack{Model: gorm.Model{ID: 99999}}, 0); err == nil {
+		t.Errorf("should failed to move not associated track")
+	}
+
+	if err := DB.Model(&moved).Association("Tracks").Replace([]PositionTrack{moved.Tracks[3], moved.Tracks[1], {Name: "e"}}); err != nil {
+		t.Fatalf("failed to replace tracks, got %v", err)
+	}
+
+	var replaced PositionPlaylist
+	DB.Preload("Tracks").First(&replaced, playlist.ID)
+	assertPositionNames(t, replaced.Tracks, "d", "b", "e")
+	for idx, track := range replaced.Tracks {
+		AssertEqual(t, track.Position, idx)
+	}
+
+	if err := DB.Model(&PositionTag{}).Association("Tracks").Move(&PositionTrack{}, 0); err == nil {
+		t.Errorf("should failed to move for unknown relation")
+	}
+}
+
+func TestPositionedMany2Many(t *testing.T) {
+	DB.Migrator().DropTable(&PositionPlaylist{}, &PositionTrack{}, "position_playlist_tags", &PositionTag{})
+	if err := DB.AutoMigrate(&PositionPlaylist{}, &PositionTrack{}, &PositionTag{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	// created in reverse name order, so ordering by primary key or name would not match positions
+	playlist := PositionPlaylist{Name: "playlist", Tags: []PositionTag{{Name: "z"}, {Name: "y"}}}
+	DB.Create(&playlist)
+
+	if err := DB.Model(&playlist).Association("Tags").Append(&PositionTag{Name: "x"}); err != nil {
+		t.Fatalf("failed to append tags, got %v", err)
+	}
+
+	var result PositionPlaylist
+	DB.Preload("Tags").First(&result, playlist.ID)
+	assertPositionNames(t, result.Tags, "z", "y", "x")
+
+	if err := DB.Model(&result).Association("Tags").Move(result.Tags[2], 0); err != nil {
+		t.Fatalf("failed to move tag, got %v", err)
+	}
+	assertPositionNames(t, result.Tags, "x", "z", "y")
+
+	var moved PositionPlaylist
+	DB.Preload("Tags").First(&moved, playlist.ID)
+	assertPositionNames(t, moved.Tags, "x", "z", "y")
+
+	var tags []PositionTag
+	DB.Model(&moved).Association("Tags").Find(&tags)
+	AssertEqual(t, len(tags), 3)
+
+	if err := DB.Model(&moved).Association("Tags").Replace(&moved.Tags[2], &moved.Tags[0]); err != nil {
+		t.Fatalf("failed to replace tags, got %v", err)
+	}
+
+	var replaced PositionPlaylist
+	DB.Preload("Tags").First(&replaced, playlist.ID)
+	assertPositionNames(t, replaced.Tags, "y", "x")
+
+	var positions []int
+	DB.Table("position_playlist_tags").Where("position_playlist_id = ?", playlist.ID).Order("position").Pluck("position", &positions)
+	AssertEqual(t, positions, []int{0, 1})
+}
+
+type PositionQueue struct {
+	gorm.Model
+	Items []PositionItem `gorm:"orderBy:rank;positioned"`
+}
+
+type PositionItem struct {
+	gorm.Model
+	Name            string
+	Rank            int64
+	PositionQueueID uint
+}
+
+type PositionInvalidQueue struct {
+	gorm.Model
+	Items []PositionItem `gorm:"foreignKey:PositionQueueID;orderBy:rank desc;positioned"`
+}
+
+func TestPositionedHasManySave(t *testing.T) {
+	DB.Migrator().DropTable(&PositionQueue{}, &PositionItem{})
+	if err := DB.AutoMigrate(&PositionQueue{}, &PositionItem{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	queue := PositionQueue{Items: []PositionItem{{Name: "a"}, {Name: "b"}, {Name: "c"}}}
+	DB.Create(&queue)
+
+	var result PositionQueue
+	DB.Preload("Items").First(&result, queue.ID)
+	assertItemRanks(t, result.Items, "a", 0, "b", 1, "c", 2)
+
+	// reordered records keep their positions, new records are positioned after them
+	result.Items = []PositionItem{result.Items[2], result.Items[0], {Name: "d"}, result.Items[1], {Name: "e", Rank: 10}}
+	if err := DB.Save(&result).Error; err != nil {
+		t.Fatalf("failed to save queue, got %v", err)
+	}
+
+	DB.Preload("Items").First(&result, queue.ID)
+	assertItemRanks(t, result.Items, "a", 0, "b", 1, "c", 2, "e", 10, "d", 11)
+
+	if err := DB.Model(&result).Association("Items").Move(result.Items[4], 1); err != nil {
+		t.Fatalf("failed to move item, got %v", err)
+	}
+
+	DB.Preload("Items").First(&result, queue.ID)
+	assertItemRanks(t, result.Items, "a", 0, "d", 1, "b", 2, "c", 3, "e", 4)
+
+	if err := DB.AutoMigrate(&PositionInvalidQueue{}); err == nil {
+		t.Errorf("should return error for invalid orderBy")
+	}
+}
+
+func assertItemRanks(t *testing.T, items []PositionItem, expects ...interface{}) {
+	t.Helper()
+	var got []interface{}
+	for _, item := range items {
+		got = append(got, item.Name, int(item.Rank))
+	}
+	AssertEqual(t, got, expects)
+}
+
+type PositionMember struct {
+	gorm.Model
+	Name string
+}
+
+type PositionBand struct {
+	gorm.Model
+	Name    string
+	Members []PositionMember `gorm:"many2many:position_band_members;orderBy:position;positioned"`
+}
+
+type PositionBandMember struct {
+	PositionBandID   uint
+	PositionMemberID uint
+	Position         int
+	DeletedAt        gorm.DeletedAt
+}
+
+func TestPositionedMany2ManyMoveSoftDeletedJoinRows(t *testing.T) {
+	DB.Migrator().DropTable(&PositionBand{}, &PositionMember{}, &PositionBandMember{})
+	if err := DB.SetupJoinTable(&PositionBand{}, "Members", &PositionBandMember{}); err != nil {
+		t.Fatalf("failed to setup join table, got %v", err)
+	}
+	if err := DB.AutoMigrate(&PositionBand{}, &PositionMember{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	band := PositionBand{Name: "band", Members: []PositionMember{{Name: "a"}, {Name: "b"}, {Name: "c"}}}
+	DB.Create(&band)
+
+	// soft deleted join row is restored when appended again
+	member := band.Members[1]
+	if err := DB.Model(&band).Association("Members").Delete(&member); err != nil {
+		t.Fatalf("failed to delete member, got %v", err)
+	}
+	if err := DB.Model(&band).Association("Members").Append(&member); err != nil {
+		t.Fatalf("failed to append member, got %v", err)
+	}
+
+	// stale soft deleted join row of b, should be kept with its position
+	deleted := PositionBandMember{PositionBandID: band.ID, PositionMemberID: member.ID, Position: 1, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}
+	if err := DB.Create(&deleted).Error; err != nil {
+		t.Fatalf("failed to create join row, got %v", err)
+	}
+
+	var updates []interface{}
+	DB.Callback().Update().Before("gorm:update").Register("test:count_position_updates", func(db *gorm.DB) {
+		if db.Statement.Table == "position_band_members" {
+			updates = append(updates, db.Statement.Dest)
+		}
+	})
+	defer DB.Callback().Update().Remove("test:count_position_updates")
+
+	var result PositionBand
+	DB.Preload("Members").First(&result, band.ID)
+	assertPositionNames(t, result.Members, "a", "c", "b")
+
+	if err := DB.Model(&result).Association("Members").Move(result.Members[2], 0); err != nil {
+		t.Fatalf("failed to move member, got %v", err)
+	}
+	assertPositionNames(t, result.Members, "b", "a", "c")
+	// c is kept at position 2
+	AssertEqual(t, len(updates), 2)
+
+	// moving to current position doesn't update any rows
+	updates = nil
+	if err := DB.Model(&result).Association("Members").Move(result.Members[0], 0); err != nil {
+		t.Fatalf("failed to move member, got %v", err)
+	}
+	AssertEqual(t, len(updates), 0)
+
+	var moved PositionBand
+	DB.Preload("Members").First(&moved, band.ID)
+	assertPositionNames(t, moved.Members, "b", "a", "c")
+
+	var stale PositionBandMember
+	DB.Unscoped().First(&stale, "position_band_id = ? AND position_member_id = ? AND deleted_at IS NOT NULL", band.ID, member.ID)
+	AssertEqual(t, stale.Position, 1)
+}
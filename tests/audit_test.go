@@ -0,0 +1,199 @@
+package tests_test
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/utils"
+)
+
+type AuditedProduct struct {
+	gorm.Model
+	Code  string
+	Name  string
+	Price uint
+}
+
+func (AuditedProduct) Auditable() bool { return true }
+
+func TestAuditPlugin(t *testing.T) {
+	db, err := OpenTestConnection(&gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect database, got error %v", err)
+	}
+
+	if err := db.Use(&gorm.AuditPlugin{Table: "product_audits"}); err != nil {
+		t.Fatalf("failed to register audit plugin, got error %v", err)
+	}
+
+	db.Migrator().DropTable(&AuditedProduct{}, "product_audits")
+	if err := db.AutoMigrate(&AuditedProduct{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+	if err := db.Table("product_audits").AutoMigrate(&gorm.AuditLog{}); err != nil {
+		t.Fatalf("failed to migrate audit table, got error %v", err)
+	}
+
+	tx := db.WithContext(gorm.WithActor(context.Background(), "jinzhu"))
+
+	products := []AuditedProduct{{Code: "A1", Name: "apple", Price: 100}, {Code: "B1", Name: "banana", Price: 200}}
+	if err := tx.Create(&products).Error; err != nil {
+		t.Fatalf("failed to create products, got error %v", err)
+	}
+
+	var logs []gorm.AuditLog
+	db.Table("product_audits").Where("action = ?", gorm.AuditActionCreate).Order("id").Find(&logs)
+	if len(logs) != 2 {
+		t.Fatalf("should have 2 create audit logs, got %v", len(logs))
+	}
+	if logs[0].Actor != "jinzhu" || logs[0].Table != "audited_products" || logs[0].NewValues["name"] != "apple" {
+		t.Errorf("invalid create audit log, got %#v", logs[0])
+	}
+
+	if err := tx.Model(&products[0]).Updates(map[string]interface{}{"name": "red apple", "price": 0}).Error; err != nil {
+		t.Fatalf("failed to update product, got error %v", err)
+	}
+
+	logs = nil
+	db.Table("product_audits").Where("action = ? AND primary_key = ?", gorm.AuditActionUpdate, products[0].ID).Find(&logs)
+	if len(logs) != 1 {
+		t.Fatalf("should have 1 update audit log, got %v", len(logs))
+	}
+	if logs[0].OldValues["name"] != "apple" || logs[0].NewValues["name"] != "red apple" {
+		t.Errorf("invalid name changes, got %v -> %v", logs[0].OldValues, logs[0].NewValues)
+	}
+	if logs[0].OldValues["price"] != float64(100) || logs[0].NewValues["price"] != float64(0) {
+		t.Errorf("invalid price changes, got %v -> %v", logs[0].OldValues, logs[0].NewValues)
+	}
+	if _, ok := logs[0].NewValues["code"]; ok {
+		t.Errorf("unchanged column should not be recorded, got %v", logs[0].NewValues)
+	}
+
+	if err := tx.Model(&AuditedProduct{}).Where("code IN ?", []string{"A1", "B1"}).Update("price", gorm.Expr("price + ?", 5)).Error; err != nil {
+		t.Fatalf("failed to batch update products, got error %v", err)
+	}
+
+	logs = nil
+	db.Table("product_audits").Where("action = ? AND primary_key IN ?", gorm.AuditActionUpdate, []uint{products[0].ID, products[1].ID}).Order("id").Find(&logs)
+	if len(logs) != 3 {
+		t.Fatalf("batch update should be recorded for each row, got %#v", logs)
+	}
+	if logs[1].PrimaryKey != utils.ToString(products[0].ID) || logs[1].OldValues["price"] != float64(0) || logs[1].NewValues["price"] != "price + 5" {
+		t.Errorf("invalid batch update audit log, got %#v", logs[1])
+	}
+	if logs[2].PrimaryKey != utils.ToString(products[1].ID) || logs[2].OldValues["price"] != float64(200) || logs[2].NewValues["price"] != "price + 5" {
+		t.Errorf("invalid batch update audit log, got %#v", logs[2])
+	}
+
+	if err := tx.Model(&AuditedProduct{}).Where("code = ?", "B1").Updates(map[string]interface{}{"name": "banana", "code": "B2"}).Error; err != nil {
+		t.Fatalf("failed to update products with map, got error %v", err)
+	}
+
+	logs = nil
+	db.Table("product_audits").Where("action = ? AND primary_key = ?", gorm.AuditActionUpdate, products[1].ID).Order("id").Find(&logs)
+	if len(logs) != 2 || logs[1].OldValues["code"] != "B1" || logs[1].NewValues["code"] != "B2" {
+		t.Errorf("invalid map update audit log, got %#v", logs)
+	}
+	if _, ok := logs[len(logs)-1].NewValues["name"]; ok {
+		t.Errorf("unchanged column should not be recorded, got %v", logs[len(logs)-1].NewValues)
+	}
+
+	oldID := products[0].ID
+	if err := tx.Model(&products[0]).Update("id", oldID+1000).Error; err != nil {
+		t.Fatalf("failed to update primary key, got error %v", err)
+	}
+
+	logs = nil
+	db.Table("product_audits").Where("action = ? AND primary_key = ?", gorm.AuditActionUpdate, oldID).Order("id").Find(&logs)
+	if len(logs) != 3 || logs[2].OldValues["id"] != float64(oldID) || logs[2].NewValues["id"] != float64(oldID+1000) {
+		t.Errorf("primary key changes should be recorded by the old primary key, got %#v", logs)
+	}
+
+	var count int64
+	db.Table("product_audits").Where("action = ?", gorm.AuditActionUpdate).Count(&count)
+	if count != 5 {
+		t.Errorf("should have 5 update audit logs, got %v", count)
+	}
+
+	if err := tx.Delete(&products[1]).Error; err != nil {
+		t.Fatalf("failed to delete product, got error %v", err)
+	}
+
+	logs = nil
+	db.Table("product_audits").Where("action = ?", gorm.AuditActionDelete).Find(&logs)
+	if len(logs) != 1 || logs[0].OldValues["name"] != "banana" || logs[0].NewValues != nil {
+		t.Errorf("invalid delete audit log, got %#v", logs)
+	}
+
+	batch := []AuditedProduct{{Code: "C1", Name: "cherry", Price: 300}, {Code: "C2", Name: "coconut", Price: 400}}
+	if err := tx.Create(&batch).Error; err != nil {
+		t.Fatalf("failed to create products, got error %v", err)
+	}
+
+	if err := tx.Where("code LIKE ?", "C%").Delete(&AuditedProduct{}).Error; err != nil {
+		t.Fatalf("failed to batch delete products, got error %v", err)
+	}
+
+	logs = nil
+	db.Table("product_audits").Where("action = ? AND primary_key IN ?", gorm.AuditActionDelete, []uint{batch[0].ID, batch[1].ID}).Order("primary_key").Find(&logs)
+	if len(logs) != 2 || logs[0].OldValues["name"] != "cherry" || logs[1].OldValues["price"] != float64(400) {
+		t.Errorf("batch delete should be recorded with the values of each row, got %#v", logs)
+	}
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		tx.Model(&products[0]).Update("name", "green apple")
+		return gorm.ErrInvalidData
+	}); err != gorm.ErrInvalidData {
+		t.Fatalf("should return rollback error, got %v", err)
+	}
+
+	db.Table("product_audits").Where("action = ?", gorm.AuditActionUpdate).Count(&count)
+	if count != 5 {
+		t.Errorf("audit logs should be rolled back with the transaction, got %v", count)
+	}
+
+	// records skipped by conflicts are not recorded
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&AuditedProduct{Model: gorm.Model{ID: products[0].ID}, Code: "A1"}).Error; err != nil {
+		t.Fatalf("failed to create product, got error %v", err)
+	}
+
+	db.Table("product_audits").Where("action = ?", gorm.AuditActionCreate).Count(&count)
+	if count != 4 {
+		t.Errorf("skipped records should not be recorded, got %v create audit logs", count)
+	}
+
+	// updates scoped by the tables of UPDATE ... FROM
+	if name := db.Dialector.Name(); name == "sqlite" || name == "postgres" {
+		db.Migrator().DropTable(&AuditedDiscount{})
+		if err := db.AutoMigrate(&AuditedDiscount{}); err != nil {
+			t.Fatalf("failed to migrate, got error %v", err)
+		}
+
+		other := AuditedProduct{Code: "D1", Name: "durian", Price: 500}
+		tx.Create(&other)
+		tx.Create(&AuditedDiscount{Code: "A1"})
+
+		if err := tx.Model(&AuditedProduct{}).Clauses(clause.From{Tables: []clause.Table{{Name: "audited_discounts"}}}).
+			Where("audited_discounts.code = audited_products.code").Update("price", 1).Error; err != nil {
+			t.Fatalf("failed to update products from discounts, got error %v", err)
+		}
+
+		logs = nil
+		db.Table("product_audits").Where("action = ? AND primary_key IN ?", gorm.AuditActionUpdate, []uint{products[0].ID, other.ID}).Order("id").Find(&logs)
+		if last := logs[len(logs)-1]; last.PrimaryKey != utils.ToString(products[0].ID) || last.NewValues["price"] != float64(1) {
+			t.Errorf("updates from other tables should be recorded for the updated rows, got %#v", last)
+		}
+
+		db.Table("product_audits").Where("action = ? AND primary_key = ?", gorm.AuditActionUpdate, other.ID).Count(&count)
+		if count != 0 {
+			t.Errorf("rows not updated should not be recorded, got %v", count)
+		}
+	}
+}
+
+type AuditedDiscount struct {
+	ID   uint
+	Code string
+}
@@ -0,0 +1,109 @@
+package tests_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type TrackedAccount struct {
+	gorm.ChangeTracker
+	ID      uint
+	Name    string
+	Balance int
+	Active  bool
+	Tags    []string               `gorm:"serializer:json"`
+	Meta    map[string]interface{} `gorm:"serializer:json"`
+	Changes map[string]gorm.Change `gorm:"-"`
+}
+
+func (a *TrackedAccount) BeforeUpdate(tx *gorm.DB) error {
+	a.Changes = tx.Statement.Changes()
+	return nil
+}
+
+func TestSaveChanges(t *testing.T) {
+	DB.Migrator().DropTable(&TrackedAccount{})
+	if err := DB.AutoMigrate(&TrackedAccount{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	account := TrackedAccount{Name: "tracked", Balance: 100, Active: true, Meta: map[string]interface{}{"level": 1}}
+	DB.Create(&account)
+
+	if err := DB.SaveChanges(&account).Error; !errors.Is(err, gorm.ErrSnapshotRequired) {
+		t.Errorf("should return snapshot required error, got %v", err)
+	}
+
+	var loaded TrackedAccount
+	if err := DB.First(&loaded, account.ID).Error; err != nil {
+		t.Fatalf("failed to find account, got error %v", err)
+	}
+
+	if changes := DB.Model(&loaded).Statement.Changes(); changes != nil {
+		t.Errorf("statement without reflect value should not have changes, got %v", changes)
+	}
+
+	loaded.Balance = 0
+	loaded.Active = false
+
+	stmt := DB.Session(&gorm.Session{DryRun: true}).SaveChanges(&loaded).Statement
+	if sql := stmt.SQL.String(); !regexp.MustCompile(`UPDATE .tracked_accounts. SET .balance.=.*,.active.=.* WHERE .id. = .*`).MatchString(sql) && !regexp.MustCompile(`UPDATE .tracked_accounts. SET .active.=.*,.balance.=.* WHERE .id. = .*`).MatchString(sql) {
+		t.Errorf("should only update changed columns, got %v", sql)
+	}
+
+	if err := DB.SaveChanges(&loaded).Error; err != nil {
+		t.Fatalf("failed to save changes, got error %v", err)
+	}
+
+	if len(loaded.Changes) != 2 || loaded.Changes["balance"].Old != 100 || loaded.Changes["balance"].New != 0 || loaded.Changes["active"].Old != true {
+		t.Errorf("hooks should get changes, got %#v", loaded.Changes)
+	}
+
+	var result TrackedAccount
+	DB.First(&result, account.ID)
+	if result.Name != "tracked" || result.Balance != 0 || result.Active {
+		t.Errorf("invalid saved account, got %#v", result)
+	}
+
+	loaded.Changes = nil
+	if err := DB.SaveChanges(&loaded).Error; err != nil || loaded.Changes != nil {
+		t.Errorf("should not update without changes, got %v, %v", err, loaded.Changes)
+	}
+
+	// in-place mutations
+	loaded.Tags = append(loaded.Tags, "vip")
+	if err := DB.SaveChanges(&loaded).Error; err != nil {
+		t.Fatalf("failed to save changes, got error %v", err)
+	}
+	if _, ok := loaded.Changes["tags"]; !ok || len(loaded.Changes) != 1 {
+		t.Errorf("appended slice should be changed, got %#v", loaded.Changes)
+	}
+
+	loaded.Meta["level"] = 2
+	loaded.Tags[0] = "svip"
+	if err := DB.SaveChanges(&loaded).Error; err != nil {
+		t.Fatalf("failed to save changes, got error %v", err)
+	}
+	if _, ok := loaded.Changes["meta"]; !ok || len(loaded.Changes) != 2 || loaded.Changes["tags"].Old.([]string)[0] != "vip" {
+		t.Errorf("edited map and slice should be changed, got %#v", loaded.Changes)
+	}
+
+	DB.First(&result, account.ID)
+	AssertEqual(t, result.Tags, []string{"svip"})
+	AssertEqual(t, result.Meta, map[string]interface{}{"level": float64(2)})
+
+	var accounts []TrackedAccount
+	DB.Find(&accounts)
+	accounts[0].Name = "tracked-2"
+	if err := DB.SaveChanges(&accounts[0]).Error; err != nil {
+		t.Fatalf("failed to save changes of record loaded in slice, got error %v", err)
+	}
+
+	if DB.First(&result, account.ID); result.Name != "tracked-2" {
+		t.Errorf("invalid saved name, got %v", result.Name)
+	}
+}
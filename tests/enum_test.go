@@ -0,0 +1,133 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/migrator"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type EnumPriority string
+
+func (EnumPriority) EnumValues() []string {
+	return []string{"low", "high"}
+}
+
+type EnumArticle struct {
+	ID       uint
+	Title    string
+	Status   string       `gorm:"default:draft" enum:"draft,published,archived"`
+	Priority EnumPriority `gorm:"default:low"`
+	Category *string      `enum:"news,blog"`
+	Order    *string      `enum:"asc,desc"`
+}
+
+type EnumArticleV2 struct {
+	ID       uint
+	Title    string
+	Status   string       `gorm:"default:draft" enum:"draft,published,archived,deleted"`
+	Priority EnumPriority `gorm:"default:low"`
+	Category *string      `enum:"news,blog"`
+	Order    *string      `enum:"asc,desc"`
+	Kind     string       `enum:"post,page"`
+}
+
+func (EnumArticleV2) TableName() string { return "enum_articles" }
+
+func TestEnum(t *testing.T) {
+	DB.Migrator().DropTable(&EnumArticle{})
+	if err := DB.AutoMigrate(&EnumArticle{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	for _, name := range []string{"chk_enum_articles_status_enum", "chk_enum_articles_priority_enum", "chk_enum_articles_category_enum", "chk_enum_articles_order_enum"} {
+		if !DB.Migrator().HasConstraint(&EnumArticle{}, name) {
+			t.Errorf("enum constraint %v should be created", name)
+		}
+	}
+
+	article := EnumArticle{Title: "default"}
+	if err := DB.Create(&article).Error; err != nil {
+		t.Fatalf("failed to create, got %v", err)
+	}
+	AssertEqual(t, article.Status, "draft")
+	AssertEqual(t, article.Priority, EnumPriority("low"))
+
+	category := "other"
+	err := DB.Create(&EnumArticle{Title: "invalid", Status: "deleted", Priority: "urgent", Category: &category}).Error
+	var validationErr *gorm.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("should returns validation error, got %v", err)
+	}
+	AssertEqual(t, validationErr.Fields(), []string{"Status", "Priority", "Category"})
+
+	if err := DB.Model(&article).Update("status", "deleted").Error; !errors.As(err, &validationErr) {
+		t.Errorf("should failed to update with invalid enum value, got %v", err)
+	}
+	if err := DB.Model(&article).Updates(EnumArticle{Status: "published"}).Error; err != nil {
+		t.Errorf("failed to update with valid enum value, got %v", err)
+	}
+	if err := DB.Model(&article).Omit("Status").Updates(map[string]interface{}{"status": "deleted", "title": "omitted"}).Error; err != nil {
+		t.Errorf("omitted enum field should not be validated, got %v", err)
+	}
+
+	// enforced by database
+	if err := DB.Exec("INSERT INTO enum_articles (title, status, priority) VALUES (?, ?, ?)", "raw", "deleted", "low").Error; err == nil {
+		t.Errorf("should failed to insert invalid enum value")
+	}
+
+	// column of reserved word is quoted in enum constraint
+	if err := DB.Exec("INSERT INTO enum_articles (title, status, priority, "+DB.Statement.Quote("order")+") VALUES (?, ?, ?, ?)", "raw", "draft", "low", "random").Error; err == nil {
+		t.Errorf("should failed to insert invalid enum value of reserved word column")
+	}
+
+	// enum values changed, check constraints are recreated with the definitions returned by dialector
+	db := DB
+	if _, ok := DB.Dialector.(migrator.CheckConstraintDefinitionInterface); !ok {
+		if err := DB.AutoMigrate(&EnumArticleV2{}); err != nil {
+			t.Fatalf("failed to migrate, got %v", err)
+		}
+		if err := DB.Exec("INSERT INTO enum_articles (title, status, priority) VALUES (?, ?, ?)", "raw", "deleted", "low").Error; err == nil {
+			t.Errorf("enum constraint should be kept if the definition is unsupported")
+		}
+
+		if db, ok = sqliteDDLDB(); !ok {
+			t.Skip("check constraint definitions are unsupported by dialector")
+		}
+	}
+
+	if err := db.AutoMigrate(&EnumArticleV2{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	if !DB.Migrator().HasConstraint(&EnumArticleV2{}, "chk_enum_articles_kind_enum") {
+		t.Errorf("enum constraint of added column should be created")
+	}
+
+	if err := DB.Create(&EnumArticleV2{Title: "deleted", Status: "deleted", Kind: "post"}).Error; err != nil {
+		t.Errorf("failed to create with new enum value, got %v", err)
+	}
+	if err := DB.Exec("INSERT INTO enum_articles (title, status, priority, kind) VALUES (?, ?, ?, ?)", "raw", "deleted", "low", "post").Error; err != nil {
+		t.Errorf("failed to insert new enum value, got %v", err)
+	}
+	if err := DB.Exec("INSERT INTO enum_articles (title, status, priority, kind) VALUES (?, ?, ?, ?)", "raw", "removed", "low", "post").Error; err == nil {
+		t.Errorf("should failed to insert invalid enum value")
+	}
+
+	var count int64
+	DB.Model(&EnumArticleV2{}).Where("status = ?", "deleted").Count(&count)
+	AssertEqual(t, count, 2)
+
+	// enum values removed
+	if err := DB.Where("status = ?", "deleted").Delete(&EnumArticleV2{}).Error; err != nil {
+		t.Fatalf("failed to delete, got %v", err)
+	}
+	if err := db.AutoMigrate(&EnumArticle{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+	if err := DB.Exec("INSERT INTO enum_articles (title, status, priority) VALUES (?, ?, ?)", "raw", "deleted", "low").Error; err == nil {
+		t.Errorf("should failed to insert removed enum value")
+	}
+}
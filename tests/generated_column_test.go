@@ -0,0 +1,172 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/migrator"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type GeneratedLineItem struct {
+	ID       uint
+	Price    float64
+	Quantity int
+	Total    float64 `gorm:"generated:price * quantity;stored"`
+	Double   int     `gorm:"generated:quantity * 2"`
+}
+
+type GeneratedLineItemV2 struct {
+	ID       uint
+	Price    float64
+	Quantity int
+	Total    float64 `gorm:"generated:price * quantity * 2;stored"`
+	Double   int     `gorm:"generated:quantity * 3"`
+}
+
+func (GeneratedLineItemV2) TableName() string { return "generated_line_items" }
+
+func TestGeneratedColumn(t *testing.T) {
+	DB.Migrator().DropTable(&GeneratedLineItem{})
+
+	db := DB
+	if _, ok := DB.Dialector.(migrator.GeneratedColumnInterface); !ok {
+		if err := DB.AutoMigrate(&GeneratedLineItem{}); !errors.Is(err, gorm.ErrUnsupportedDriver) {
+			t.Fatalf("should return unsupported driver error, got %v", err)
+		}
+
+		if db, ok = sqliteDDLDB(); !ok {
+			t.Skip("generated columns are unsupported by dialector")
+		}
+	}
+
+	if err := db.AutoMigrate(&GeneratedLineItem{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	if err := db.AutoMigrate(&GeneratedLineItem{}); err != nil {
+		t.Fatalf("failed to migrate again, got %v", err)
+	}
+
+	stmt := db.Model(&GeneratedLineItem{}).Statement
+	stmt.Parse(&GeneratedLineItem{})
+	total, double := stmt.Schema.LookUpField("Total"), stmt.Schema.LookUpField("Double")
+	if total.Creatable || total.Updatable || !total.Readable || !total.GeneratedStored || double.GeneratedStored {
+		t.Errorf("invalid generated field %#v", total)
+	}
+
+	item := GeneratedLineItem{Price: 2.5, Quantity: 4, Total: 99}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatalf("failed to create, got %v", err)
+	}
+	AssertEqual(t, item.Total, 10.0)
+	AssertEqual(t, item.Double, 8)
+
+	items := []GeneratedLineItem{{Price: 1, Quantity: 1}, {Price: 2, Quantity: 3}}
+	if err := db.Create(&items).Error; err != nil {
+		t.Fatalf("failed to create, got %v", err)
+	}
+	AssertEqual(t, items[0].Total, 1.0)
+	AssertEqual(t, items[1].Total, 6.0)
+
+	if err := db.Model(&item).Updates(GeneratedLineItem{Quantity: 10, Total: 1}).Error; err != nil {
+		t.Fatalf("failed to update, got %v", err)
+	}
+	AssertEqual(t, item.Total, 25.0)
+	AssertEqual(t, item.Double, 20)
+
+	item.Price = 3
+	item.Total = 1
+	if err := db.Save(&item).Error; err != nil {
+		t.Fatalf("failed to save, got %v", err)
+	}
+
+	var result GeneratedLineItem
+	db.First(&result, item.ID)
+	AssertEqual(t, result.Total, 30.0)
+	AssertEqual(t, result.Double, 20)
+
+	// created by save with generated returning cleared
+	saved := GeneratedLineItem{ID: item.ID + 100, Price: 1, Quantity: 2}
+	if err := db.Save(&saved).Error; err != nil {
+		t.Fatalf("failed to save new record, got %v", err)
+	}
+	var result2 GeneratedLineItem
+	db.First(&result2, saved.ID)
+	AssertEqual(t, result2.Total, 2.0)
+	AssertEqual(t, saved.ID, result2.ID)
+
+	// expressions of stored and virtual columns changed
+	if err := db.AutoMigrate(&GeneratedLineItemV2{}); err != nil {
+		t.Fatalf("failed to migrate changed expression, got %v", err)
+	}
+
+	var result3 GeneratedLineItemV2
+	db.First(&result3, item.ID)
+	AssertEqual(t, result3.Total, 60.0)
+	AssertEqual(t, result3.Double, 30)
+
+	var count int64
+	db.Model(&GeneratedLineItemV2{}).Count(&count)
+	AssertEqual(t, count, int64(4))
+
+	if err := db.AutoMigrate(&GeneratedLineItemV2{}); err != nil {
+		t.Fatalf("failed to migrate again, got %v", err)
+	}
+
+	result3 = GeneratedLineItemV2{}
+	db.First(&result3, saved.ID)
+	AssertEqual(t, result3.Total, 4.0)
+}
+
+// noReturningDialector registers create, update callbacks without RETURNING, e.g: MySQL
+type noReturningDialector struct {
+	gorm.Dialector
+}
+
+func (d noReturningDialector) Initialize(db *gorm.DB) error {
+	if err := d.Dialector.Initialize(db); err != nil {
+		return err
+	}
+
+	// SQLite returns the last inserted id
+	db.Callback().Create().Replace("gorm:create", callbacks.Create(&callbacks.Config{LastInsertIDReversed: d.Name() == "sqlite"}))
+	db.Callback().Update().Replace("gorm:update", callbacks.Update(&callbacks.Config{}))
+	return nil
+}
+
+func TestGeneratedColumnWithoutReturning(t *testing.T) {
+	db := DB
+	if _, ok := DB.Dialector.(migrator.GeneratedColumnInterface); !ok {
+		if db, ok = sqliteDDLDB(); !ok {
+			t.Skip("generated columns are unsupported by dialector")
+		}
+	}
+
+	db.Migrator().DropTable(&GeneratedLineItem{})
+	if err := db.AutoMigrate(&GeneratedLineItem{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	noReturningDB, err := gorm.Open(noReturningDialector{Dialector: DB.Dialector}, &gorm.Config{Logger: DB.Logger})
+	if err != nil {
+		t.Fatalf("failed to open db, got %v", err)
+	}
+
+	// generated fields are reloaded by primary keys
+	items := []*GeneratedLineItem{{Price: 1, Quantity: 2}, {Price: 2, Quantity: 3}}
+	if err := noReturningDB.Create(&items).Error; err != nil {
+		t.Fatalf("failed to create, got %v", err)
+	}
+	AssertEqual(t, items[0].Total, 2.0)
+	AssertEqual(t, items[1].Total, 6.0)
+	AssertEqual(t, items[1].Double, 6)
+
+	if err := noReturningDB.Model(items[0]).Updates(GeneratedLineItem{Quantity: 5}).Error; err != nil {
+		t.Fatalf("failed to update, got %v", err)
+	}
+	AssertEqual(t, items[0].Total, 5.0)
+	AssertEqual(t, items[0].Double, 10)
+}
@@ -0,0 +1,142 @@
+package tests_test
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/schema"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type GeneratedOrder struct {
+	ID    int64  `gorm:"primaryKey;generator:snowflake"`
+	Code  string `gorm:"generator:ulid"`
+	Name  string
+	Items []GeneratedOrderItem
+}
+
+type GeneratedOrderItem struct {
+	ID               string `gorm:"primaryKey;size:36;generator:uuidv7"`
+	GeneratedOrderID int64
+	Token            string `gorm:"size:36;generator:uuidv4"`
+	Hash             []byte `gorm:"generator:uuid"`
+	Name             string
+}
+
+var (
+	uuidV4Regexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	uuidV7Regexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	ulidRegexp   = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+)
+
+func TestGenerator(t *testing.T) {
+	DB.Migrator().DropTable(&GeneratedOrder{}, &GeneratedOrderItem{})
+	if err := DB.AutoMigrate(&GeneratedOrder{}, &GeneratedOrderItem{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	orders := []GeneratedOrder{
+		{Name: "order 1", Items: []GeneratedOrderItem{{Name: "item 1"}, {Name: "item 2"}}},
+		{Name: "order 2", Code: "custom", Items: []GeneratedOrderItem{{Name: "item 3", ID: "custom"}}},
+	}
+	if err := DB.Create(&orders).Error; err != nil {
+		t.Fatalf("failed to create, got %v", err)
+	}
+
+	if orders[0].ID == 0 || orders[1].ID <= orders[0].ID {
+		t.Errorf("snowflake ids should be generated in order, got %v, %v", orders[0].ID, orders[1].ID)
+	}
+
+	if !ulidRegexp.MatchString(orders[0].Code) {
+		t.Errorf("invalid ulid %v", orders[0].Code)
+	}
+	AssertEqual(t, orders[1].Code, "custom")
+
+	items := append(orders[0].Items, orders[1].Items...)
+	for _, item := range items[:2] {
+		if !uuidV7Regexp.MatchString(item.ID) || !uuidV4Regexp.MatchString(item.Token) || len(item.Hash) != 16 {
+			t.Errorf("invalid generated values %#v", item)
+		}
+		AssertEqual(t, item.GeneratedOrderID, orders[0].ID)
+	}
+	AssertEqual(t, items[2].ID, "custom")
+	if items[0].ID == items[1].ID || items[0].Token == items[1].Token {
+		t.Errorf("generated values should be unique, got %#v", items[:2])
+	}
+
+	var result GeneratedOrder
+	if err := DB.Preload("Items").First(&result, orders[0].ID).Error; err != nil {
+		t.Fatalf("failed to find order, got %v", err)
+	}
+	AssertEqual(t, result.Code, orders[0].Code)
+	AssertEqual(t, len(result.Items), 2)
+
+	order := GeneratedOrder{Name: "order 3"}
+	if err := DB.Save(&order).Error; err != nil || order.ID == 0 {
+		t.Errorf("failed to save with generated id, got %v, %v", order.ID, err)
+	}
+
+	if _, err := schema.Parse(&struct {
+		ID string `gorm:"generator:unknown"`
+	}{}, &sync.Map{}, schema.NamingStrategy{}); err == nil {
+		t.Errorf("should failed to parse with unknown generator")
+	}
+}
+
+func TestGeneratorOrdering(t *testing.T) {
+	stringType := reflect.TypeOf("")
+	field := &schema.Field{Name: "ID", FieldType: stringType, IndirectFieldType: stringType}
+
+	for _, name := range []string{"uuidv7", "ulid"} {
+		generator, ok := schema.GetGenerator(name)
+		if !ok {
+			t.Fatalf("generator %v not found", name)
+		}
+
+		first, err := generator.Generate(context.Background(), field)
+		if err != nil {
+			t.Fatalf("failed to generate %v, got %v", name, err)
+		}
+		time.Sleep(2 * time.Millisecond)
+		second, _ := generator.Generate(context.Background(), field)
+
+		if first.(string) >= second.(string) {
+			t.Errorf("%v should be sortable by time, got %v, %v", name, first, second)
+		}
+	}
+
+	int64Type := reflect.TypeOf(int64(0))
+	int64Field := &schema.Field{Name: "ID", FieldType: int64Type, IndirectFieldType: int64Type}
+	for _, node := range []int64{-1, 1024} {
+		if _, err := schema.NewSnowflakeGenerator(node); err == nil {
+			t.Errorf("should return error for node id %v out of range", node)
+		}
+	}
+
+	if _, err := (&schema.SnowflakeGenerator{Node: 1024}).Generate(context.Background(), int64Field); err == nil {
+		t.Errorf("should return error when generating with node id out of range")
+	}
+
+	generator, err := schema.NewSnowflakeGenerator(1023)
+	if err != nil {
+		t.Fatalf("failed to create snowflake generator, got %v", err)
+	}
+
+	var last int64
+	for i := 0; i < 5000; i++ {
+		value, _ := generator.Generate(context.Background(), int64Field)
+		id := value.(int64)
+		if id <= last || id>>12&0x3ff != 1023 {
+			t.Fatalf("snowflake id should be increasing with node id, got %v after %v", id, last)
+		}
+		last = id
+	}
+
+	if _, err := generator.Generate(context.Background(), field); err != nil {
+		t.Errorf("should generate snowflake id for string field, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package tests_test
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type ThroughCountry struct {
+	ID    uint
+	Name  string
+	Users []ThroughUser
+	Posts []*ThroughPost `gorm:"through:Users"`
+}
+
+type ThroughUser struct {
+	gorm.Model
+	Name             string
+	ThroughCountryID uint
+	Posts            []ThroughPost
+}
+
+type ThroughPost struct {
+	gorm.Model
+	Title         string
+	ThroughUserID uint
+}
+
+func TestHasManyThrough(t *testing.T) {
+	DB.Migrator().DropTable(&ThroughCountry{}, &ThroughUser{}, &ThroughPost{})
+	if err := DB.AutoMigrate(&ThroughCountry{}, &ThroughUser{}, &ThroughPost{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	countries := []ThroughCountry{
+		{Name: "country-1", Users: []ThroughUser{
+			{Name: "user-1", Posts: []ThroughPost{{Title: "post-1"}, {Title: "post-2"}}},
+			{Name: "user-2", Posts: []ThroughPost{{Title: "post-3"}}},
+			{Name: "user-3"},
+		}},
+		{Name: "country-2", Users: []ThroughUser{{Name: "user-4", Posts: []ThroughPost{{Title: "post-4"}}}}},
+		{Name: "country-3"},
+	}
+	if err := DB.Create(&countries).Error; err != nil {
+		t.Fatalf("failed to create countries, got error %v", err)
+	}
+
+	var posts []ThroughPost
+	if DB.Find(&posts); len(posts) != 4 {
+		t.Fatalf("has many through relation should not be saved, got %v posts", len(posts))
+	}
+
+	checkPosts := func(name string, got []*ThroughPost, expects ...string) {
+		t.Helper()
+		titles := make([]string, 0, len(got))
+		for _, post := range got {
+			titles = append(titles, post.Title)
+		}
+		sort.Strings(titles)
+
+		if len(titles) != len(expects) {
+			t.Fatalf("%v: expects posts %v, got %v", name, expects, titles)
+		}
+		for idx, title := range titles {
+			if title != expects[idx] {
+				t.Errorf("%v: expects posts %v, got %v", name, expects, titles)
+			}
+		}
+	}
+
+	var results []ThroughCountry
+	if err := DB.Preload("Posts").Order("id").Find(&results).Error; err != nil {
+		t.Fatalf("failed to preload posts, got error %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("invalid countries, got %v", len(results))
+	}
+	checkPosts("preload", results[0].Posts, "post-1", "post-2", "post-3")
+	checkPosts("preload", results[1].Posts, "post-4")
+	checkPosts("preload", results[2].Posts)
+
+	var result ThroughCountry
+	if err := DB.Preload("Posts", "title <> ?", "post-1").First(&result, countries[0].ID).Error; err != nil {
+		t.Fatalf("failed to preload posts, got error %v", err)
+	}
+	checkPosts("preload with conditions", result.Posts, "post-2", "post-3")
+
+	var joined []ThroughCountry
+	if err := DB.Joins("Posts").Order("through_countries.id").Find(&joined).Error; err != nil {
+		t.Fatalf("failed to join posts, got error %v", err)
+	}
+
+	if len(joined) != 3 {
+		t.Fatalf("invalid countries, got %v", len(joined))
+	}
+	checkPosts("joins", joined[0].Posts, "post-1", "post-2", "post-3")
+	checkPosts("joins", joined[1].Posts, "post-4")
+	checkPosts("joins", joined[2].Posts)
+
+	var found []*ThroughPost
+	if err := DB.Model(&countries[0]).Association("Posts").Find(&found); err != nil {
+		t.Fatalf("failed to find posts, got error %v", err)
+	}
+	checkPosts("association", found, "post-1", "post-2", "post-3")
+
+	if count := DB.Model(&countries).Association("Posts").Count(); count != 4 {
+		t.Errorf("invalid association count, got %v", count)
+	}
+
+	// soft deleted intermediate records should be excluded
+	DB.Delete(&countries[0].Users[1])
+	if count := DB.Model(&countries[0]).Association("Posts").Count(); count != 2 {
+		t.Errorf("invalid association count after deleting user, got %v", count)
+	}
+
+	if err := DB.Model(&countries[0]).Association("Posts").Append(&ThroughPost{Title: "post-5"}); !errors.Is(err, gorm.ErrUnsupportedRelation) {
+		t.Errorf("has many through relation should be read-only, got %v", err)
+	}
+
+	if err := DB.Model(&countries[0]).Association("Posts").Clear(); !errors.Is(err, gorm.ErrUnsupportedRelation) {
+		t.Errorf("has many through relation should be read-only, got %v", err)
+	}
+}
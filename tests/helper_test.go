@@ -8,7 +8,11 @@ import (
 	"testing"
 	"time"
 
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
 
 	. "gorm.io/gorm/utils/tests"
 )
@@ -288,3 +292,139 @@ func db(unscoped bool) *gorm.DB {
 		return DB
 	}
 }
+
+// sqliteDDLDialector emulates SQLite driver supporting generated columns and check constraint definitions, which are
+// parsed from the table DDL
+type sqliteDDLDialector struct {
+	gorm.Dialector
+}
+
+type sqliteDDLMigrator struct {
+	sqlite.Migrator
+}
+
+// sqliteDDLDB returns DB with sqliteDDLDialector, returns false if not SQLite
+func sqliteDDLDB() (*gorm.DB, bool) {
+	if DB.Dialector.Name() != "sqlite" {
+		return nil, false
+	}
+
+	tx := DB.Session(&gorm.Session{})
+	tx.Dialector = sqliteDDLDialector{Dialector: DB.Dialector}
+	return tx, true
+}
+
+func (d sqliteDDLDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return sqliteDDLMigrator{sqlite.Migrator{Migrator: migrator.Migrator{Config: migrator.Config{
+		DB:                          db,
+		Dialector:                   d,
+		CreateIndexAfterCreateTable: true,
+	}}}}
+}
+
+func (d sqliteDDLDialector) GeneratedDataTypeOf(field *schema.Field) string {
+	dataType := d.DataTypeOf(field) + " GENERATED ALWAYS AS (" + field.GeneratedExpression + ")"
+	if field.GeneratedStored {
+		dataType += " STORED"
+	}
+	return dataType
+}
+
+func (d sqliteDDLDialector) GeneratedExpressionOf(db *gorm.DB, table, column string) (string, error) {
+	definition, err := definitionInDDL(db, table, column)
+	if idx := strings.Index(strings.ToUpper(definition), " AS "); idx >= 0 {
+		return parenthesized(definition[idx:]), err
+	}
+	return "", err
+}
+
+func (d sqliteDDLDialector) CheckConstraintDefinition(db *gorm.DB, table, name string) (string, error) {
+	definition, err := definitionInDDL(db, table, name)
+	if idx := strings.Index(strings.ToUpper(definition), "CHECK"); idx >= 0 {
+		return "CHECK (" + parenthesized(definition[idx:]) + ")", err
+	}
+	return "", err
+}
+
+// definitionInDDL returns the definition following the quoted name in the table DDL, e.g: `name` CHECK (...)
+func definitionInDDL(db *gorm.DB, table, name string) (string, error) {
+	var ddl string
+	if err := db.Raw("SELECT sql FROM sqlite_master WHERE type = ? AND tbl_name = ?", "table", table).Row().Scan(&ddl); err != nil {
+		return "", err
+	}
+
+	for _, quoted := range []string{"`" + name + "`", `"` + name + `"`} {
+		if idx := strings.Index(ddl, quoted); idx >= 0 {
+			definition, level := ddl[idx+len(quoted):], 0
+			for i, c := range definition {
+				switch {
+				case c == '(':
+					level++
+				case c == ')' && level == 0, c == ',' && level == 0:
+					return definition[:i], nil
+				case c == ')':
+					level--
+				}
+			}
+			return definition, nil
+		}
+	}
+	return "", nil
+}
+
+// parenthesized returns the content of the first parentheses of s
+func parenthesized(s string) string {
+	start, level := strings.Index(s, "("), 0
+	for i := start; start >= 0 && i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			level++
+		case ')':
+			if level--; level == 0 {
+				return s[start+1 : i]
+			}
+		}
+	}
+	return ""
+}
+
+// AddColumn recreates the table to add stored generated column, which can't be added by SQLite, the copied table
+// should have no indexes and references
+func (m sqliteDDLMigrator) AddColumn(value interface{}, name string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if field := stmt.Schema.LookUpField(name); field == nil || !field.GeneratedStored {
+			return m.Migrator.AddColumn(value, name)
+		}
+
+		var columns []clause.Column
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && field.GeneratedExpression == "" && m.HasColumn(value, field.DBName) {
+				columns = append(columns, clause.Column{Name: field.DBName})
+			}
+		}
+
+		tempTable := clause.Table{Name: stmt.Table + "__temp"}
+		return m.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("ALTER TABLE ? RENAME TO ?", clause.Table{Name: stmt.Table}, tempTable).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Migrator().CreateTable(value); err != nil {
+				return err
+			}
+
+			if err := tx.Exec("INSERT INTO ? ? SELECT ? FROM ?", clause.Table{Name: stmt.Table}, columns, clause.CommaExpression{Exprs: columnExprs(columns)}, tempTable).Error; err != nil {
+				return err
+			}
+			return tx.Exec("DROP TABLE ?", tempTable).Error
+		})
+	})
+}
+
+func columnExprs(columns []clause.Column) []clause.Expression {
+	exprs := make([]clause.Expression, 0, len(columns))
+	for _, column := range columns {
+		exprs = append(exprs, clause.Expr{SQL: "?", Vars: []interface{}{column}})
+	}
+	return exprs
+}
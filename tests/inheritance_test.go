@@ -0,0 +1,187 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type Vehicler interface {
+	WheelCount() int
+}
+
+type InheritanceVehicle struct {
+	ID     uint
+	Kind   string
+	Name   string
+	Wheels int
+}
+
+func (v InheritanceVehicle) WheelCount() int {
+	return v.Wheels
+}
+
+type InheritanceCar struct {
+	InheritanceVehicle `gorm:"inheritance:single;discriminator:kind"`
+	Doors              int
+}
+
+type InheritanceTruck struct {
+	InheritanceVehicle `gorm:"inheritance:single;discriminator:kind;discriminatorValue:truck"`
+	Payload            int
+}
+
+func TestSingleTableInheritance(t *testing.T) {
+	DB.Migrator().DropTable("inheritance_vehicles")
+	if err := DB.AutoMigrate(&InheritanceCar{}, &InheritanceTruck{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	if DB.Migrator().HasTable("inheritance_cars") || DB.Migrator().HasTable("inheritance_trucks") {
+		t.Fatalf("subtypes should be stored in the table of base")
+	}
+
+	for _, column := range []string{"id", "kind", "name", "wheels", "doors", "payload"} {
+		if !DB.Migrator().HasColumn("inheritance_vehicles", column) {
+			t.Errorf("column %v should be migrated", column)
+		}
+	}
+
+	cars := []InheritanceCar{
+		{InheritanceVehicle: InheritanceVehicle{Name: "sedan", Wheels: 4}, Doors: 4},
+		{InheritanceVehicle: InheritanceVehicle{Name: "coupe", Wheels: 4}, Doors: 2},
+	}
+	truck := InheritanceTruck{InheritanceVehicle: InheritanceVehicle{Name: "hauler", Wheels: 18}, Payload: 20}
+	DB.Create(&cars)
+	DB.Create(&truck)
+	DB.Model(&InheritanceTruck{}).Create(map[string]interface{}{"name": "pickup", "wheels": 4, "payload": 1})
+
+	AssertEqual(t, cars[0].Kind, "InheritanceCar")
+	AssertEqual(t, truck.Kind, "truck")
+
+	var names []string
+	DB.Table("inheritance_vehicles").Where("kind = ?", "truck").Order("id").Pluck("name", &names)
+	AssertEqual(t, names, []string{"hauler", "pickup"})
+
+	var foundCars []InheritanceCar
+	if err := DB.Order("id").Find(&foundCars).Error; err != nil || len(foundCars) != 2 {
+		t.Fatalf("should only find cars, got error %v, length %v", err, len(foundCars))
+	}
+	AssertEqual(t, foundCars[1].Doors, 2)
+
+	var trucks []InheritanceTruck
+	DB.Where("wheels = ?", 4).Or("wheels = ?", 18).Find(&trucks)
+	AssertEqual(t, len(trucks), 2)
+
+	var count int64
+	DB.Model(&InheritanceCar{}).Count(&count)
+	AssertEqual(t, count, 2)
+
+	var car InheritanceCar
+	if err := DB.First(&car, truck.ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("should not find truck as car, got %v", err)
+	}
+
+	if err := DB.Model(&InheritanceCar{}).Update("wheels", 3).Error; !errors.Is(err, gorm.ErrMissingWhereClause) {
+		t.Errorf("should require where conditions to update, got %v", err)
+	}
+
+	if result := DB.Model(&InheritanceCar{}).Where("wheels = ?", 4).Update("wheels", 3); result.Error != nil || result.RowsAffected != 2 {
+		t.Errorf("should only update cars, got error %v, rows affected %v", result.Error, result.RowsAffected)
+	}
+
+	if result := DB.Where("wheels = ?", 3).Delete(&InheritanceTruck{}); result.Error != nil || result.RowsAffected != 0 {
+		t.Errorf("should not delete cars as trucks, got error %v, rows affected %v", result.Error, result.RowsAffected)
+	}
+
+	var vehicles []Vehicler
+	if err := DB.Model(&InheritanceVehicle{}).Order("id").Find(&vehicles).Error; err != nil {
+		t.Fatalf("failed to find vehicles, got %v", err)
+	}
+
+	if len(vehicles) != 4 {
+		t.Fatalf("should find all vehicles, got %v", len(vehicles))
+	}
+
+	if v, ok := vehicles[1].(*InheritanceCar); !ok || v.Name != "coupe" || v.Doors != 2 || v.WheelCount() != 3 {
+		t.Errorf("vehicle should be instantiated as car, got %#v", vehicles[1])
+	}
+
+	if v, ok := vehicles[2].(*InheritanceTruck); !ok || v.Name != "hauler" || v.Payload != 20 || v.WheelCount() != 18 {
+		t.Errorf("vehicle should be instantiated as truck, got %#v", vehicles[2])
+	}
+
+	var carVehicles []Vehicler
+	DB.Model(&InheritanceCar{}).Find(&carVehicles)
+	AssertEqual(t, len(carVehicles), 2)
+}
+
+type Shaper interface {
+	SideCount() int
+}
+
+type InheritanceShape struct {
+	ID    uint
+	Shape string
+	Sides int
+}
+
+func (s InheritanceShape) SideCount() int {
+	return s.Sides
+}
+
+type InheritanceSquare struct {
+	InheritanceShape `gorm:"inheritance:single;discriminator:shape;discriminatorValue:square"`
+	Length           int
+}
+
+type InheritanceCircle struct {
+	InheritanceShape `gorm:"inheritance:single;discriminator:shape;discriminatorValue:circle"`
+	Radius           int
+}
+
+func TestSetupSubtypes(t *testing.T) {
+	DB.Migrator().DropTable("inheritance_shapes")
+	if err := DB.AutoMigrate(&InheritanceShape{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	DB.Exec("ALTER TABLE inheritance_shapes ADD length integer")
+	DB.Exec("ALTER TABLE inheritance_shapes ADD radius integer")
+	DB.Table("inheritance_shapes").Create([]map[string]interface{}{
+		{"shape": "square", "sides": 4, "length": 2},
+		{"shape": "circle", "sides": 0, "radius": 3},
+	})
+
+	if err := DB.SetupSubtypes(&InheritanceShape{}, &InheritanceSquare{}); err != nil {
+		t.Fatalf("failed to setup subtypes, got %v", err)
+	}
+
+	if err := DB.SetupSubtypes(&InheritanceShape{}, &InheritanceCar{}); err == nil {
+		t.Errorf("should return error for subtype of other base")
+	}
+
+	var shapes []Shaper
+	if err := DB.Model(&InheritanceShape{}).Order("id").Find(&shapes).Error; !errors.Is(err, gorm.ErrUnregisteredSubtype) {
+		t.Fatalf("should return error for unregistered subtype, got %v", err)
+	}
+
+	if err := DB.SetupSubtypes(&InheritanceShape{}, &InheritanceSquare{}, &InheritanceCircle{}); err != nil {
+		t.Fatalf("failed to setup subtypes, got %v", err)
+	}
+
+	shapes = nil
+	if err := DB.Model(&InheritanceShape{}).Order("id").Find(&shapes).Error; err != nil || len(shapes) != 2 {
+		t.Fatalf("failed to find shapes, got error %v, length %v", err, len(shapes))
+	}
+
+	if v, ok := shapes[0].(*InheritanceSquare); !ok || v.Length != 2 || v.SideCount() != 4 {
+		t.Errorf("shape should be instantiated as square, got %#v", shapes[0])
+	}
+
+	if v, ok := shapes[1].(*InheritanceCircle); !ok || v.Radius != 3 {
+		t.Errorf("shape should be instantiated as circle, got %#v", shapes[1])
+	}
+}
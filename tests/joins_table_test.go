@@ -114,3 +114,236 @@ func TestOverrideJoinTable(t *testing.T) {
 		t.Errorf("person's addresses expects 2, got %v", count)
 	}
 }
+
+func TestSoftDeleteJoinTable(t *testing.T) {
+	DB.Migrator().DropTable(&Person{}, &Address{}, &PersonAddress{})
+
+	if err := DB.SetupJoinTable(&Person{}, "Addresses", &PersonAddress{}); err != nil {
+		t.Fatalf("Failed to setup join table for person, got error %v", err)
+	}
+
+	if err := DB.AutoMigrate(&Person{}, &Address{}); err != nil {
+		t.Fatalf("Failed to migrate, got %v", err)
+	}
+
+	person := Person{Name: "person", Addresses: []Address{{Name: "address 1"}, {Name: "address 2"}}}
+	DB.Create(&person)
+	deleted := person.Addresses[0]
+
+	if err := DB.Model(&person).Association("Addresses").Delete(&deleted); err != nil {
+		t.Fatalf("Failed to delete address, got error %v", err)
+	}
+
+	var preloaded Person
+	if err := DB.Preload("Addresses").First(&preloaded, person.ID).Error; err != nil || len(preloaded.Addresses) != 1 {
+		t.Fatalf("soft deleted join records should be excluded from preload, got error %v, length: %v", err, len(preloaded.Addresses))
+	}
+
+	var names []string
+	if err := DB.Model(&Person{}).Joins("Addresses").Where("people.id = ?", person.ID).Pluck("Addresses.name", &names).Error; err != nil || len(names) != 1 {
+		t.Fatalf("soft deleted join records should be excluded from joins, got error %v, names: %v", err, names)
+	}
+
+	if err := DB.Model(&person).Association("Addresses").Append(&deleted); err != nil {
+		t.Fatalf("Failed to append address, got error %v", err)
+	}
+
+	var joinRecords []PersonAddress
+	DB.Unscoped().Find(&joinRecords, "person_id = ?", person.ID)
+	if len(joinRecords) != 2 {
+		t.Fatalf("soft deleted join record should be revived rather than inserted, got %v records", len(joinRecords))
+	}
+
+	for _, joinRecord := range joinRecords {
+		if joinRecord.DeletedAt.Valid {
+			t.Errorf("join record of address %v should be revived", joinRecord.AddressID)
+		}
+	}
+
+	if count := DB.Model(&person).Association("Addresses").Count(); count != 2 {
+		t.Errorf("person's addresses expects 2, got %v", count)
+	}
+
+	if err := DB.Model(&person).Association("Addresses").Replace(&person.Addresses[1]); err != nil {
+		t.Fatalf("Failed to replace addresses, got error %v", err)
+	}
+
+	if err := DB.Preload("Addresses").First(&preloaded, person.ID).Error; err != nil || len(preloaded.Addresses) != 1 {
+		t.Fatalf("replaced join records should be soft deleted, got error %v, length: %v", err, len(preloaded.Addresses))
+	}
+
+	if count := DB.Unscoped().Model(&PersonAddress{}).Where("person_id = ?", person.ID).Find(&[]PersonAddress{}).RowsAffected; count != 2 {
+		t.Errorf("replaced join records should be soft deleted, got %v records", count)
+	}
+}
+
+type PivotUser struct {
+	ID       uint
+	Name     string
+	Projects []PivotProject `gorm:"many2many:pivot_members"`
+}
+
+type PivotProject struct {
+	ID     uint
+	Name   string
+	Member PivotMember `gorm:"-"`
+}
+
+type PivotMember struct {
+	PivotUserID    uint `gorm:"primaryKey"`
+	PivotProjectID uint `gorm:"primaryKey"`
+	Role           string
+	CreatedAt      time.Time
+}
+
+func TestJoinTablePivot(t *testing.T) {
+	DB.Migrator().DropTable(&PivotUser{}, &PivotProject{}, &PivotMember{})
+
+	if err := DB.SetupJoinTable(&PivotUser{}, "Projects", &PivotMember{}); err != nil {
+		t.Fatalf("Failed to setup join table, got error %v", err)
+	}
+
+	if err := DB.AutoMigrate(&PivotUser{}, &PivotProject{}); err != nil {
+		t.Fatalf("Failed to migrate, got %v", err)
+	}
+
+	user := PivotUser{Name: "pivot", Projects: []PivotProject{{Name: "project-1", Member: PivotMember{Role: "owner"}}}}
+	if err := DB.Create(&user).Error; err != nil {
+		t.Fatalf("Failed to create user, got %v", err)
+	}
+
+	user2 := PivotUser{Name: "pivot-2"}
+	DB.Create(&user2)
+
+	project := PivotProject{Name: "project-2"}
+	if err := DB.Model(&user).Association("Projects").Append(gorm.WithPivot(&project, PivotMember{Role: "admin"})); err != nil {
+		t.Fatalf("Failed to append project, got %v", err)
+	}
+
+	if err := DB.Model(&user2).Association("Projects").Append(gorm.WithPivot(&user.Projects[0], &PivotMember{Role: "viewer"})); err != nil {
+		t.Fatalf("Failed to append project, got %v", err)
+	}
+
+	var members []PivotMember
+	DB.Order("pivot_user_id, pivot_project_id").Find(&members)
+	if len(members) != 3 || members[0].Role != "owner" || members[1].Role != "admin" || members[2].Role != "viewer" {
+		t.Fatalf("invalid join records, got %#v", members)
+	}
+
+	// update join values of existing join record
+	if err := DB.Model(&user).Association("Projects").Append(gorm.WithPivot(&project, PivotMember{Role: "maintainer"})); err != nil {
+		t.Fatalf("Failed to append project, got %v", err)
+	}
+
+	var member PivotMember
+	if DB.First(&member, "pivot_user_id = ? AND pivot_project_id = ?", user.ID, project.ID); member.Role != "maintainer" {
+		t.Errorf("join record should be updated, got %#v", member)
+	}
+
+	AssertAssociationCount(t, user, "Projects", 2, "AfterAppend")
+
+	var users []PivotUser
+	if err := DB.Preload("Projects", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Order("id").Find(&users).Error; err != nil {
+		t.Fatalf("Failed to preload projects, got %v", err)
+	}
+
+	if len(users) != 2 || len(users[0].Projects) != 2 || len(users[1].Projects) != 1 {
+		t.Fatalf("invalid preloaded projects, got %#v", users)
+	}
+
+	if users[0].Projects[0].Member.Role != "owner" || users[0].Projects[1].Member.Role != "maintainer" {
+		t.Errorf("join records should be preloaded, got %#v", users[0].Projects)
+	}
+
+	if users[1].Projects[0].ID != users[0].Projects[0].ID || users[1].Projects[0].Member.Role != "viewer" {
+		t.Errorf("join records should be preloaded for each owner, got %#v", users[1].Projects)
+	}
+
+	if err := DB.Model(&user).Association("Projects").Replace(gorm.WithPivot(&project, PivotMember{Role: "owner"})); err != nil {
+		t.Fatalf("Failed to replace projects, got %v", err)
+	}
+
+	DB.Order("pivot_user_id, pivot_project_id").Find(&members)
+	if len(members) != 2 || members[0].PivotProjectID != project.ID || members[0].Role != "owner" {
+		t.Errorf("invalid join records after replace, got %#v", members)
+	}
+}
+
+type SurrogateUser struct {
+	ID       uint
+	Name     string
+	Projects []SurrogateProject `gorm:"many2many:surrogate_members"`
+}
+
+type SurrogateProject struct {
+	ID   uint
+	Name string
+}
+
+type SurrogateMember struct {
+	ID                 uint
+	SurrogateUserID    uint
+	SurrogateProjectID uint
+	Role               string
+	DeletedAt          gorm.DeletedAt
+}
+
+func TestJoinTablePivotWithSurrogateKey(t *testing.T) {
+	DB.Migrator().DropTable(&SurrogateUser{}, &SurrogateProject{}, &SurrogateMember{})
+
+	if err := DB.SetupJoinTable(&SurrogateUser{}, "Projects", &SurrogateMember{}); err != nil {
+		t.Fatalf("Failed to setup join table, got error %v", err)
+	}
+
+	if err := DB.AutoMigrate(&SurrogateUser{}, &SurrogateProject{}); err != nil {
+		t.Fatalf("Failed to migrate, got %v", err)
+	}
+
+	user := SurrogateUser{Name: "surrogate"}
+	DB.Create(&user)
+
+	projects := []SurrogateProject{{Name: "project-1"}, {Name: "project-2"}}
+	if err := DB.Model(&user).Association("Projects").Append(gorm.WithPivot(&projects[0], SurrogateMember{Role: "admin"}), &projects[1]); err != nil {
+		t.Fatalf("Failed to append projects, got %v", err)
+	}
+
+	// existing join records are found by foreign keys
+	if err := DB.Model(&user).Association("Projects").Append(gorm.WithPivot(&projects[0], SurrogateMember{Role: "maintainer"}), &projects[1]); err != nil {
+		t.Fatalf("Failed to append projects again, got %v", err)
+	}
+
+	var members []SurrogateMember
+	DB.Order("surrogate_project_id").Find(&members)
+	if len(members) != 2 || members[0].Role != "maintainer" || members[1].SurrogateProjectID != projects[1].ID {
+		t.Errorf("join records should not be duplicated, got %#v", members)
+	}
+
+	if err := DB.Model(&user).Association("Projects").Delete(&projects[0]); err != nil {
+		t.Fatalf("Failed to delete project, got %v", err)
+	}
+
+	// soft deleted join records are revived with join model values
+	if err := DB.Model(&user).Association("Projects").Append(gorm.WithPivot(&projects[0], SurrogateMember{Role: "owner"})); err != nil {
+		t.Fatalf("Failed to append deleted project, got %v", err)
+	}
+
+	members = nil
+	DB.Unscoped().Order("surrogate_project_id").Find(&members)
+	if len(members) != 2 || members[0].Role != "owner" || members[0].DeletedAt.Valid {
+		t.Errorf("soft deleted join record should be revived rather than inserted, got %#v", members)
+	}
+
+	AssertAssociationCount(t, user, "Projects", 2, "AfterRevive")
+
+	// new values are created with join records in the same transaction
+	if err := DB.Model(&user).Association("Projects").Append(gorm.WithPivot(&SurrogateProject{Name: "project-3"}, PivotMember{Role: "admin"})); err == nil {
+		t.Fatalf("should fail to append project with invalid pivot")
+	}
+
+	var count int64
+	if DB.Model(&SurrogateProject{}).Where("name = ?", "project-3").Count(&count); count != 0 {
+		t.Errorf("project should be rolled back with invalid pivot, got %v", count)
+	}
+}
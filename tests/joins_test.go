@@ -400,3 +400,127 @@ func TestNestedJoins(t *testing.T) {
 		CheckPet(t, *user.Manager.NamedPet, *users2[idx].Manager.NamedPet)
 	}
 }
+
+func TestJoinsHasManyAndMany2Many(t *testing.T) {
+	users := []User{
+		*GetUser("joins-collection-1", Config{Pets: 2, Languages: 2, Account: true}),
+		*GetUser("joins-collection-2", Config{Pets: 1}),
+		*GetUser("joins-collection-3", Config{}),
+	}
+	for _, user := range users {
+		for _, pet := range user.Pets {
+			pet.Toy = Toy{Name: pet.Name + "_toy"}
+		}
+	}
+
+	if err := DB.Create(&users).Error; err != nil {
+		t.Fatalf("failed to create users, got error %v", err)
+	}
+
+	var results []User
+	if err := DB.Joins("Pets").Joins("Pets.Toy").Joins("Languages").Joins("Account").
+		Where("users.id IN ?", []uint{users[0].ID, users[1].ID, users[2].ID}).Order("users.id").Find(&results).Error; err != nil {
+		t.Fatalf("failed to find users with joins, got error %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("rows of the same user should be merged, got %v users", len(results))
+	}
+
+	for idx, user := range results {
+		if user.ID != users[idx].ID || len(user.Pets) != len(users[idx].Pets) || len(user.Languages) != len(users[idx].Languages) {
+			t.Fatalf("invalid joined associations, expects %v pets, %v languages, got %#v", len(users[idx].Pets), len(users[idx].Languages), user)
+		}
+
+		sort.Slice(user.Pets, func(i, j int) bool { return user.Pets[i].ID < user.Pets[j].ID })
+		for i, pet := range user.Pets {
+			CheckPet(t, *pet, *users[idx].Pets[i])
+			if pet.Toy.Name != pet.Name+"_toy" {
+				t.Errorf("nested toy should be joined, got %v", pet.Toy.Name)
+			}
+		}
+
+		if user.Account.Number != users[idx].Account.Number {
+			t.Errorf("account should be joined, got %v", user.Account.Number)
+		}
+	}
+
+	var user User
+	if err := DB.InnerJoins("Pets", DB.Where(&Pet{Name: "joins-collection-1_pet_2"})).Joins("Languages").Find(&user).Error; err != nil {
+		t.Fatalf("failed to find user with joins, got error %v", err)
+	}
+
+	if user.ID != users[0].ID || len(user.Pets) != 1 || user.Pets[0].Name != "joins-collection-1_pet_2" || len(user.Languages) != 2 {
+		t.Errorf("should filter by joined associations, got %#v", user)
+	}
+
+	DB.Delete(&users[0].Pets[0])
+	if err := DB.Joins("Pets").Where("users.id = ?", users[0].ID).Find(&results).Error; err != nil {
+		t.Fatalf("failed to find users with joins, got error %v", err)
+	}
+
+	if len(results) != 1 || len(results[0].Pets) != 1 {
+		t.Errorf("soft deleted pets should not be joined, got %#v", results)
+	}
+}
+
+func TestJoinsHasManyWithLimit(t *testing.T) {
+	users := []User{
+		*GetUser("joins-limit-1", Config{Pets: 3, Languages: 2, Company: true}),
+		*GetUser("joins-limit-2", Config{Pets: 2, Company: true}),
+		*GetUser("joins-limit-3", Config{Pets: 1, Company: true}),
+	}
+
+	if err := DB.Create(&users).Error; err != nil {
+		t.Fatalf("failed to create users, got error %v", err)
+	}
+
+	ids := []uint{users[0].ID, users[1].ID, users[2].ID}
+
+	var user User
+	if err := DB.Joins("Pets").Joins("Languages").Where("users.id IN ?", ids).First(&user).Error; err != nil {
+		t.Fatalf("failed to first user with joins, got error %v", err)
+	}
+
+	if user.ID != users[0].ID || len(user.Pets) != 3 || len(user.Languages) != 2 {
+		t.Errorf("limit should apply to users, expects 3 pets, 2 languages, got %v pets, %v languages", len(user.Pets), len(user.Languages))
+	}
+
+	var last User
+	if err := DB.Joins("Pets").Where("users.id IN ?", ids).Last(&last).Error; err != nil {
+		t.Fatalf("failed to last user with joins, got error %v", err)
+	}
+
+	if last.ID != users[2].ID || len(last.Pets) != 1 {
+		t.Errorf("invalid last user, got %#v", last)
+	}
+
+	var results []User
+	if err := DB.Joins("Pets").Where("users.id IN ?", ids).Order("users.id").Limit(2).Offset(1).Find(&results).Error; err != nil {
+		t.Fatalf("failed to find users with joins, got error %v", err)
+	}
+
+	if len(results) != 2 || results[0].ID != users[1].ID || len(results[0].Pets) != 2 || results[1].ID != users[2].ID || len(results[1].Pets) != 1 {
+		t.Errorf("limit, offset should apply to users, got %#v", results)
+	}
+
+	results = nil
+	if err := DB.Joins("Pets").Where("users.id IN ?", ids).Where("Pets.name IN ?", []string{"joins-limit-1_pet_1", "joins-limit-1_pet_2", "joins-limit-2_pet_1", "joins-limit-3_pet_1"}).
+		Order("users.id").Limit(2).Find(&results).Error; err != nil {
+		t.Fatalf("failed to find users with joined conditions, got error %v", err)
+	}
+
+	if len(results) != 2 || results[0].ID != users[0].ID || len(results[0].Pets) != 2 || results[1].ID != users[1].ID || len(results[1].Pets) != 1 {
+		t.Errorf("limit should apply to users filtered by joined pets, got %#v", results)
+	}
+
+	results = nil
+	if err := DB.Joins("Company").Joins("Pets").Where("users.id IN ?", ids).Where("Company.name <> ?", "company-joins-limit-1").
+		Order("users.id").Limit(1).Find(&results).Error; err != nil {
+		t.Fatalf("failed to find users with joined belongs to conditions, got error %v", err)
+	}
+
+	if len(results) != 1 || results[0].ID != users[1].ID || len(results[0].Pets) != 2 {
+		t.Errorf("limit should apply to users filtered by joined company, got %#v", results)
+	}
+}
@@ -0,0 +1,97 @@
+package tests_test
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestLoad(t *testing.T) {
+	users := []User{*GetUser("load_1", Config{Pets: 2, Company: true}), *GetUser("load_2", Config{Pets: 1, Toys: 2})}
+	for _, pet := range append(users[0].Pets, users[1].Pets...) {
+		pet.Toy = Toy{Name: pet.Name + "_toy"}
+	}
+
+	if err := DB.Create(&users).Error; err != nil {
+		t.Fatalf("failed to create users, got error %v", err)
+	}
+
+	var results []User
+	if err := DB.Where("id IN ?", []uint{users[0].ID, users[1].ID}).Order("id").Find(&results).Error; err != nil {
+		t.Fatalf("failed to find users, got error %v", err)
+	}
+
+	if err := DB.Load(&results, "Pets.Toy").Error; err != nil {
+		t.Fatalf("failed to load pets, got error %v", err)
+	}
+
+	for idx, user := range results {
+		if len(user.Pets) != len(users[idx].Pets) {
+			t.Fatalf("pets should be loaded, expects %v, got %v", len(users[idx].Pets), len(user.Pets))
+		}
+
+		for _, pet := range user.Pets {
+			if pet.Toy.Name != pet.Name+"_toy" {
+				t.Errorf("nested toy should be loaded, got %v", pet.Toy.Name)
+			}
+		}
+	}
+
+	var user User
+	DB.First(&user, users[0].ID)
+	if err := DB.Load(&user, "Pets", "name = ?", "load_1_pet_2").Error; err != nil {
+		t.Fatalf("failed to load pets, got error %v", err)
+	}
+
+	if len(user.Pets) != 1 || user.Pets[0].Name != "load_1_pet_2" {
+		t.Errorf("should load pets with conditions, got %#v", user.Pets)
+	}
+
+	if err := DB.Load(&user, clause.Associations).Error; err != nil {
+		t.Fatalf("failed to load associations, got error %v", err)
+	}
+
+	if user.Company.Name != users[0].Company.Name || len(user.Pets) != 2 {
+		t.Errorf("all associations should be loaded, got company %v, %v pets", user.Company.Name, len(user.Pets))
+	}
+
+	results[0].Toys = []Toy{{Name: "cached"}}
+	results[1].Toys = nil
+	if err := DB.Load(&results, "Toys", gorm.SkipLoaded).Error; err != nil {
+		t.Fatalf("failed to load toys, got error %v", err)
+	}
+
+	if len(results[0].Toys) != 1 || results[0].Toys[0].Name != "cached" {
+		t.Errorf("loaded associations should be skipped, got %#v", results[0].Toys)
+	}
+
+	if len(results[1].Toys) != 2 {
+		t.Errorf("toys should be loaded, got %v", len(results[1].Toys))
+	}
+
+	// nested associations of the loaded associations are still loaded
+	var loaded []User
+	DB.Preload("Pets").Where("id IN ?", []uint{users[0].ID, users[1].ID}).Order("id").Find(&loaded)
+	loaded[1].Pets = nil
+	if err := DB.Load(&loaded, "Pets.Toy", gorm.SkipLoaded).Error; err != nil {
+		t.Fatalf("failed to load nested toys, got error %v", err)
+	}
+
+	for idx, user := range loaded {
+		if len(user.Pets) != len(users[idx].Pets) {
+			t.Fatalf("pets should be loaded, expects %v, got %v", len(users[idx].Pets), len(user.Pets))
+		}
+
+		for _, pet := range user.Pets {
+			if pet.Toy.Name != pet.Name+"_toy" {
+				t.Errorf("nested toy of loaded pets should be loaded, got %v", pet.Toy.Name)
+			}
+		}
+	}
+
+	if err := DB.Load(&User{}, "Unknown").Error; err == nil {
+		t.Errorf("should return error for unsupported relation")
+	}
+}
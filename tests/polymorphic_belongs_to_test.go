@@ -0,0 +1,122 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type Commentable interface {
+	CommentableTitle() string
+}
+
+type PolymorphicPost struct {
+	ID       uint
+	Title    string
+	Comments []PolymorphicComment `gorm:"polymorphic:Commentable;polymorphicValue:posts"`
+}
+
+func (p PolymorphicPost) CommentableTitle() string {
+	return p.Title
+}
+
+type PolymorphicVideo struct {
+	ID   uint
+	Name string
+}
+
+func (v *PolymorphicVideo) CommentableTitle() string {
+	return v.Name
+}
+
+type PolymorphicComment struct {
+	ID              uint
+	Body            string
+	CommentableID   uint
+	CommentableType string
+	Commentable     Commentable `gorm:"polymorphic:Commentable"`
+}
+
+func TestPolymorphicBelongsTo(t *testing.T) {
+	DB.Migrator().DropTable(&PolymorphicPost{}, &PolymorphicVideo{}, &PolymorphicComment{})
+	if err := DB.AutoMigrate(&PolymorphicPost{}, &PolymorphicVideo{}, &PolymorphicComment{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	if err := DB.SetupPolymorphicType(&PolymorphicComment{}, "Commentable", "videos", &PolymorphicVideo{}); err != nil {
+		t.Fatalf("failed to setup polymorphic type, got %v", err)
+	}
+
+	if err := DB.SetupPolymorphicType(&PolymorphicComment{}, "Body", "videos", &PolymorphicVideo{}); err == nil {
+		t.Errorf("should failed to setup polymorphic type for non polymorphic relation")
+	}
+
+	post := PolymorphicPost{Title: "post", Comments: []PolymorphicComment{{Body: "post comment 1"}, {Body: "post comment 2"}}}
+	DB.Create(&post)
+
+	videos := []PolymorphicVideo{{Name: "video 1"}, {Name: "video 2"}}
+	DB.Create(&videos)
+
+	DB.Create(&[]PolymorphicComment{
+		{Body: "video comment 1", CommentableID: videos[0].ID, CommentableType: "videos"},
+		{Body: "video comment 2", CommentableID: videos[1].ID, CommentableType: "videos"},
+		{Body: "orphan comment"},
+	})
+
+	var comments []PolymorphicComment
+	if err := DB.Preload("Commentable").Order("id").Find(&comments).Error; !errors.Is(err, gorm.ErrUnsupportedRelation) {
+		t.Fatalf("owners should be registered explicitly even if parsed, got %v", err)
+	}
+
+	if err := DB.SetupPolymorphicType(&PolymorphicComment{}, "Commentable", "posts", &PolymorphicPost{}); err != nil {
+		t.Fatalf("failed to setup polymorphic type, got %v", err)
+	}
+
+	comments = nil
+	if err := DB.Preload("Commentable").Order("id").Find(&comments).Error; err != nil {
+		t.Fatalf("failed to preload commentable, got %v", err)
+	}
+
+	if len(comments) != 5 {
+		t.Fatalf("should find all comments, got %v", len(comments))
+	}
+
+	for _, comment := range comments[:2] {
+		if p, ok := comment.Commentable.(*PolymorphicPost); !ok || p.ID != post.ID {
+			t.Errorf("commentable of %v should be post, got %#v", comment.Body, comment.Commentable)
+		}
+	}
+
+	for idx, comment := range comments[2:4] {
+		if v, ok := comment.Commentable.(*PolymorphicVideo); !ok || v.ID != videos[idx].ID {
+			t.Errorf("commentable of %v should be video, got %#v", comment.Body, comment.Commentable)
+		}
+	}
+
+	if comments[4].Commentable != nil {
+		t.Errorf("orphan comment should not have commentable, got %#v", comments[4].Commentable)
+	}
+
+	var comment PolymorphicComment
+	if err := DB.Preload("Commentable", "name <> ?", "video 1").First(&comment, comments[2].ID).Error; err != nil {
+		t.Fatalf("failed to preload commentable, got %v", err)
+	}
+	AssertEqual(t, comment.Commentable, nil)
+
+	var comment2 PolymorphicComment
+	if err := DB.Preload("Commentable").First(&comment2, comments[3].ID).Error; err != nil || comment2.Commentable == nil {
+		t.Fatalf("failed to preload commentable, got %v", err)
+	}
+	AssertEqual(t, comment2.Commentable.CommentableTitle(), "video 2")
+
+	DB.Create(&PolymorphicComment{Body: "unknown comment", CommentableID: 1, CommentableType: "unknowns"})
+	if err := DB.Preload("Commentable").Find(&comments).Error; !errors.Is(err, gorm.ErrUnsupportedRelation) {
+		t.Errorf("should failed to preload unknown polymorphic type, got %v", err)
+	}
+
+	if err := DB.Joins("Commentable").Find(&comments).Error; !errors.Is(err, gorm.ErrUnsupportedRelation) {
+		t.Errorf("should failed to join polymorphic belongs to relation, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package tests_test
+
+import (
+	"sort"
+	"testing"
+)
+
+type TaggablePost struct {
+	ID    uint
+	Title string
+	Tags  []TaggableTag `gorm:"many2many:taggables;polymorphic:Taggable"`
+}
+
+type TaggableVideo struct {
+	ID   uint
+	Name string
+	Tags []TaggableTag `gorm:"many2many:taggables;polymorphic:Taggable;polymorphicValue:video"`
+}
+
+type TaggableTag struct {
+	ID   uint
+	Name string
+}
+
+func TestPolymorphicMany2Many(t *testing.T) {
+	DB.Migrator().DropTable(&TaggablePost{}, &TaggableVideo{}, &TaggableTag{}, "taggables")
+	if err := DB.AutoMigrate(&TaggablePost{}, &TaggableVideo{}, &TaggableTag{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	for _, column := range []string{"taggable_id", "taggable_type", "taggable_tag_id"} {
+		if !DB.Migrator().HasColumn("taggables", column) {
+			t.Errorf("join table should have column %v", column)
+		}
+	}
+
+	tags := []TaggableTag{{Name: "tag-1"}, {Name: "tag-2"}, {Name: "tag-3"}}
+	DB.Create(&tags)
+
+	post := TaggablePost{ID: 1, Title: "post", Tags: []TaggableTag{tags[0], tags[1]}}
+	video := TaggableVideo{ID: 1, Name: "video", Tags: []TaggableTag{tags[1], tags[2]}}
+	if err := DB.Create(&post).Error; err != nil {
+		t.Fatalf("failed to create post, got error %v", err)
+	}
+	if err := DB.Create(&video).Error; err != nil {
+		t.Fatalf("failed to create video, got error %v", err)
+	}
+
+	var count int64
+	if DB.Table("taggables").Where("taggable_type = ?", "taggable_posts").Count(&count); count != 2 {
+		t.Errorf("join table records should use table name as type, got %v", count)
+	}
+
+	if DB.Table("taggables").Where("taggable_type = ?", "video").Count(&count); count != 2 {
+		t.Errorf("join table records should use polymorphic value as type, got %v", count)
+	}
+
+	checkTags := func(name string, got []TaggableTag, expects ...string) {
+		t.Helper()
+		names := make([]string, 0, len(got))
+		for _, tag := range got {
+			names = append(names, tag.Name)
+		}
+		sort.Strings(names)
+
+		if len(names) != len(expects) {
+			t.Fatalf("%v: expects tags %v, got %v", name, expects, names)
+		}
+		for idx, n := range names {
+			if n != expects[idx] {
+				t.Errorf("%v: expects tags %v, got %v", name, expects, names)
+			}
+		}
+	}
+
+	var post2 TaggablePost
+	DB.Preload("Tags").First(&post2, post.ID)
+	checkTags("preload", post2.Tags, "tag-1", "tag-2")
+
+	var video2 TaggableVideo
+	DB.Joins("Tags").Find(&video2, "taggable_videos.id = ?", video.ID)
+	checkTags("joins", video2.Tags, "tag-2", "tag-3")
+
+	if count := DB.Model(&post).Association("Tags").Count(); count != 2 {
+		t.Errorf("invalid association count, got %v", count)
+	}
+
+	if err := DB.Model(&post).Association("Tags").Append(&tags[2]); err != nil {
+		t.Fatalf("failed to append tags, got error %v", err)
+	}
+
+	var tags2 []TaggableTag
+	DB.Model(&post).Association("Tags").Find(&tags2)
+	checkTags("append", tags2, "tag-1", "tag-2", "tag-3")
+
+	if err := DB.Model(&post).Association("Tags").Delete(&tags[1]); err != nil {
+		t.Fatalf("failed to delete tags, got error %v", err)
+	}
+
+	DB.Model(&post).Association("Tags").Find(&tags2)
+	checkTags("delete", tags2, "tag-1", "tag-3")
+
+	DB.Model(&video).Association("Tags").Find(&tags2)
+	checkTags("delete should not affect other types", tags2, "tag-2", "tag-3")
+
+	if err := DB.Model(&video).Association("Tags").Replace(&tags[0]); err != nil {
+		t.Fatalf("failed to replace tags, got error %v", err)
+	}
+
+	DB.Model(&video).Association("Tags").Find(&tags2)
+	checkTags("replace", tags2, "tag-1")
+
+	DB.Model(&post).Association("Tags").Find(&tags2)
+	checkTags("replace should not affect other types", tags2, "tag-1", "tag-3")
+
+	if err := DB.Model(&post).Association("Tags").Clear(); err != nil {
+		t.Fatalf("failed to clear tags, got error %v", err)
+	}
+
+	if count := DB.Model(&post).Association("Tags").Count(); count != 0 {
+		t.Errorf("tags should be cleared, got %v", count)
+	}
+
+	if count := DB.Model(&video).Association("Tags").Count(); count != 1 {
+		t.Errorf("clear should not affect other types, got %v", count)
+	}
+}
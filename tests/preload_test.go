@@ -500,3 +500,105 @@ func TestEmbedPreload(t *testing.T) {
 		})
 	}
 }
+
+func TestPreloadWithPerParentLimit(t *testing.T) {
+	users := []User{*GetUser("per_parent_limit_1", Config{Pets: 3, Toys: 3, Languages: 3}), *GetUser("per_parent_limit_2", Config{Pets: 1, Toys: 2, Languages: 1})}
+	for _, user := range users {
+		for _, pet := range user.Pets {
+			pet.Toy = Toy{Name: pet.Name + "_toy"}
+		}
+	}
+
+	if err := DB.Create(&users).Error; err != nil {
+		t.Fatalf("failed to create users, got error %v", err)
+	}
+
+	DB.Delete(&users[0].Toys[0])
+
+	// languages are limited by the join records of each user
+	if err := DB.Model(&users[1]).Association("Languages").Append(&users[0].Languages[2]); err != nil {
+		t.Fatalf("failed to append language, got error %v", err)
+	}
+
+	// associations are limited with window function, or one query for each parent
+	windowDB := DB.Session(&gorm.Session{})
+	windowDB.Dialector = windowFunctionDialector{Dialector: DB.Dialector}
+	noWindowDB := DB.Session(&gorm.Session{})
+	noWindowDB.Dialector = noWindowFunctionDialector{Dialector: DB.Dialector}
+
+	for name, db := range map[string]*gorm.DB{"window": windowDB, "no_window": noWindowDB, "default": DB} {
+		var results []User
+		if err := db.Preload("Pets", gorm.PreloadLimit{Limit: 2, Order: "name DESC"}).Preload("Pets.Toy").
+			Where("id IN ?", []uint{users[0].ID, users[1].ID}).Order("id").Find(&results).Error; err != nil {
+			t.Fatalf("%v: failed to preload, got error %v", name, err)
+		}
+
+		if len(results) != 2 || len(results[0].Pets) != 2 || len(results[1].Pets) != 1 {
+			t.Fatalf("%v: should preload at most 2 pets for each user, got %#v", name, results)
+		}
+
+		if results[0].Pets[0].Name != "per_parent_limit_1_pet_3" || results[0].Pets[1].Name != "per_parent_limit_1_pet_2" {
+			t.Errorf("%v: pets should be ordered, got %v, %v", name, results[0].Pets[0].Name, results[0].Pets[1].Name)
+		}
+
+		for _, pet := range results[0].Pets {
+			if pet.Toy.Name != pet.Name+"_toy" {
+				t.Errorf("%v: nested toy should be preloaded, got %v", name, pet.Toy.Name)
+			}
+		}
+
+		if err := db.Preload("Toys", gorm.PreloadLimit{Limit: 2}, "name <> ?", "per_parent_limit_1_toy_2").
+			Where("id IN ?", []uint{users[0].ID, users[1].ID}).Order("id").Find(&results).Error; err != nil {
+			t.Fatalf("%v: failed to preload, got error %v", name, err)
+		}
+
+		if len(results[0].Toys) != 1 || results[0].Toys[0].Name != "per_parent_limit_1_toy_3" {
+			t.Errorf("%v: should preload toys with conditions, got %#v", name, results[0].Toys)
+		}
+
+		if len(results[1].Toys) != 2 || results[1].Toys[0].ID > results[1].Toys[1].ID {
+			t.Errorf("%v: toys should be ordered by primary key, got %#v", name, results[1].Toys)
+		}
+
+		results = nil
+		if err := db.Preload("Languages", gorm.PreloadLimit{Limit: 2, Order: "code DESC"}, "name <> ?", "per_parent_limit_1_locale_3").
+			Where("id IN ?", []uint{users[0].ID, users[1].ID}).Order("id").Find(&results).Error; err != nil {
+			t.Fatalf("%v: failed to preload languages, got error %v", name, err)
+		}
+
+		if len(results[0].Languages) != 2 || results[0].Languages[0].Code != "per_parent_limit_1_locale_2" || results[0].Languages[1].Code != "per_parent_limit_1_locale_1" {
+			t.Errorf("%v: should preload 2 languages ordered by code, got %#v", name, results[0].Languages)
+		}
+
+		if len(results[1].Languages) != 1 || results[1].Languages[0].Code != "per_parent_limit_2_locale_1" {
+			t.Errorf("%v: should preload languages of each user, got %#v", name, results[1].Languages)
+		}
+
+		var user User
+		if err := db.Preload("Pets", gorm.PreloadLimit{Limit: 1, Order: "name"}).First(&user, users[0].ID).Error; err != nil {
+			t.Fatalf("%v: failed to preload, got error %v", name, err)
+		}
+
+		if len(user.Pets) != 1 || user.Pets[0].Name != "per_parent_limit_1_pet_1" {
+			t.Errorf("%v: should preload 1 pet of single user, got %#v", name, user.Pets)
+		}
+	}
+}
+
+// windowFunctionDialector reports window function support of dialector
+type windowFunctionDialector struct {
+	gorm.Dialector
+}
+
+func (windowFunctionDialector) SupportWindowFunction() bool {
+	return true
+}
+
+// noWindowFunctionDialector disables window function of dialector
+type noWindowFunctionDialector struct {
+	gorm.Dialector
+}
+
+func (noWindowFunctionDialector) SupportWindowFunction() bool {
+	return false
+}
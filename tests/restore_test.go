@@ -0,0 +1,129 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type RestorableProduct struct {
+	ID        uint
+	Name      string
+	Restored  int `gorm:"-"`
+	DeletedAt gorm.DeletedAtUnix
+}
+
+func (p *RestorableProduct) BeforeRestore(tx *gorm.DB) error {
+	if p.Name == "locked" {
+		return errors.New("can't restore locked product")
+	}
+	p.Restored++
+	return nil
+}
+
+func (p *RestorableProduct) AfterRestore(tx *gorm.DB) error {
+	p.Restored++
+	return nil
+}
+
+func TestRestore(t *testing.T) {
+	user := *GetUser("restore", Config{})
+	DB.Create(&user)
+	DB.Delete(&user)
+
+	if err := DB.First(&User{}, user.ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("should not find soft deleted user, got %v", err)
+	}
+
+	if err := DB.Restore(&user).Error; err != nil {
+		t.Fatalf("failed to restore user, got error %v", err)
+	}
+
+	if user.DeletedAt.Valid {
+		t.Errorf("user's deleted at should be reset, got %v", user.DeletedAt)
+	}
+
+	if err := DB.First(&User{}, user.ID).Error; err != nil {
+		t.Errorf("should find restored user, got error %v", err)
+	}
+
+	if err := DB.Model(&User{}).Restore(&User{}).Error; !errors.Is(err, gorm.ErrMissingWhereClause) {
+		t.Errorf("should return missing where clause error, got %v", err)
+	}
+
+	if err := DB.Restore(&Language{}, "code = ?", "restore").Error; !errors.Is(err, gorm.ErrSoftDeleteRequired) {
+		t.Errorf("should return soft delete required error, got %v", err)
+	}
+}
+
+func TestRestoreWithHooks(t *testing.T) {
+	DB.Migrator().DropTable(&RestorableProduct{})
+	if err := DB.AutoMigrate(&RestorableProduct{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	products := []RestorableProduct{{Name: "restore-1"}, {Name: "restore-2"}, {Name: "locked"}}
+	DB.Create(&products)
+	DB.Where("1 = 1").Delete(&RestorableProduct{})
+
+	var product RestorableProduct
+	if err := DB.Restore(&product, "name = ?", "restore-1").Error; err != nil {
+		t.Fatalf("failed to restore by conditions, got error %v", err)
+	}
+
+	if product.Restored != 2 {
+		t.Errorf("hooks should be called, got %v", product.Restored)
+	}
+
+	if err := DB.Restore(&products[2]).Error; err == nil {
+		t.Errorf("should return error from BeforeRestore hook")
+	}
+
+	result := DB.Model(&RestorableProduct{}).Where("name LIKE ?", "restore-%").Restore(&RestorableProduct{})
+	if result.Error != nil || result.RowsAffected != 1 {
+		t.Errorf("should restore 1 product in batch, got %v, %v", result.RowsAffected, result.Error)
+	}
+
+	var count int64
+	if DB.Model(&RestorableProduct{}).Count(&count); count != 2 {
+		t.Errorf("should find 2 restored products, got %v", count)
+	}
+}
+
+func TestRestoreAssociations(t *testing.T) {
+	user := *GetUser("restore_associations", Config{Pets: 3})
+	DB.Create(&user)
+
+	DB.Delete(user.Pets[2])
+
+	deletedAt := time.Now().Add(time.Hour).Round(time.Second)
+	tx := DB.Session(&gorm.Session{NowFunc: func() time.Time { return deletedAt }})
+	if err := tx.Select("Pets").Delete(&user).Error; err != nil {
+		t.Fatalf("failed to delete user with pets, got error %v", err)
+	}
+
+	var count int64
+	if DB.Model(&Pet{}).Where("user_id = ?", user.ID).Count(&count); count != 0 {
+		t.Fatalf("pets should be soft deleted, got %v", count)
+	}
+
+	if err := DB.Select("Pets").Restore(&User{}, user.ID).Error; err != nil {
+		t.Fatalf("failed to restore user with pets, got error %v", err)
+	}
+
+	if err := DB.First(&User{}, user.ID).Error; err != nil {
+		t.Errorf("should find restored user, got error %v", err)
+	}
+
+	var pets []Pet
+	if DB.Where("user_id = ?", user.ID).Order("id").Find(&pets); len(pets) != 2 {
+		t.Fatalf("should restore the pets deleted with user, got %v", len(pets))
+	}
+
+	if pets[0].Name != user.Pets[0].Name || pets[1].Name != user.Pets[1].Name {
+		t.Errorf("invalid restored pets, got %v, %v", pets[0].Name, pets[1].Name)
+	}
+}
@@ -227,3 +227,315 @@ func TestSerializerAssignFirstOrCreate(t *testing.T) {
 	AssertEqual(t, result.Roles, data.Roles)
 	AssertEqual(t, result.JobInfo.Location, data.JobInfo.Location)
 }
+
+type EncryptedUser struct {
+	ID         uint
+	Name       string
+	Email      string            `gorm:"serializer:encrypted"`
+	EmailIndex string            `gorm:"serializer:encrypted;blindIndex:Email;index"`
+	Phone      *string           `gorm:"serializer:encrypted"`
+	Profile    map[string]string `gorm:"serializer:encrypted"`
+}
+
+type EncryptedUserV1 struct {
+	ID         uint
+	Name       string
+	Email      string  `gorm:"serializer:encrypted_v1"`
+	EmailIndex string  `gorm:"serializer:encrypted_v1;blindIndex:Email;index"`
+	Phone      *string `gorm:"serializer:encrypted_v1"`
+}
+
+func (EncryptedUserV1) TableName() string { return "encrypted_users" }
+
+func TestEncryptedSerializer(t *testing.T) {
+	indexKey := []byte("blind index key")
+	oldKeys := map[string][]byte{"v1": bytes.Repeat([]byte("1"), 32)}
+	oldKeyring, err := schema.NewKeyring("v1", oldKeys, indexKey)
+	if err != nil {
+		t.Fatalf("failed to create keyring, got %v", err)
+	}
+	keyring, err := schema.NewKeyring("v2", map[string][]byte{"v1": oldKeys["v1"], "v2": bytes.Repeat([]byte("2"), 16)}, indexKey)
+	if err != nil {
+		t.Fatalf("failed to create keyring, got %v", err)
+	}
+	schema.RegisterSerializer("encrypted_v1", schema.EncryptedSerializer{Keyring: oldKeyring})
+	schema.RegisterSerializer("encrypted", schema.EncryptedSerializer{Keyring: keyring})
+
+	if _, err := schema.NewKeyring("v3", oldKeys, indexKey); err == nil {
+		t.Errorf("should failed to create keyring without primary key")
+	}
+	if _, err := schema.NewKeyring("v1", map[string][]byte{"v1": []byte("short")}, indexKey); err == nil {
+		t.Errorf("should failed to create keyring with invalid key")
+	}
+
+	DB.Migrator().DropTable(&EncryptedUser{})
+	if err := DB.AutoMigrate(&EncryptedUser{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	phone := "+1 555 0100"
+	oldUser := EncryptedUserV1{Name: "old", Email: "old@example.org", Phone: &phone}
+	if err := DB.Create(&oldUser).Error; err != nil {
+		t.Fatalf("failed to create user, got %v", err)
+	}
+
+	user := EncryptedUser{Name: "new", Email: "new@example.org", Profile: map[string]string{"city": "Shanghai"}}
+	if err := DB.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user, got %v", err)
+	}
+
+	var raw struct {
+		Email      string
+		EmailIndex string
+		Phone      *string
+	}
+	DB.Table("encrypted_users").Where("id = ?", oldUser.ID).Scan(&raw)
+	if !strings.HasPrefix(raw.Email, "v1:") || strings.Contains(raw.Email, "old@example.org") || raw.Phone == nil || !strings.HasPrefix(*raw.Phone, "v1:") {
+		t.Errorf("value should be encrypted with key v1, got %#v", raw)
+	}
+	AssertEqual(t, raw.EmailIndex, keyring.BlindIndex("old@example.org"))
+
+	DB.Table("encrypted_users").Where("id = ?", user.ID).Scan(&raw)
+	if !strings.HasPrefix(raw.Email, "v2:") || raw.Phone != nil {
+		t.Errorf("value should be encrypted with primary key v2, got %#v", raw)
+	}
+
+	var users []EncryptedUser
+	if err := DB.Order("id").Find(&users).Error; err != nil {
+		t.Fatalf("failed to find users, got %v", err)
+	}
+	if len(users) != 2 || users[0].Email != "old@example.org" || users[0].Phone == nil || *users[0].Phone != phone || users[1].Email != "new@example.org" || users[1].Phone != nil {
+		t.Fatalf("failed to decrypt users, got %#v", users)
+	}
+	AssertEqual(t, users[1].Profile, user.Profile)
+
+	var result EncryptedUser
+	if err := DB.Where("email_index = ?", keyring.BlindIndex("new@example.org")).First(&result).Error; err != nil || result.ID != user.ID {
+		t.Errorf("failed to find user by blind index, got %v, %v", result.ID, err)
+	}
+
+	var result2 EncryptedUser
+	if err := DB.Where(&EncryptedUser{Email: "old@example.org"}, "EmailIndex").First(&result2).Error; err != nil || result2.ID != oldUser.ID {
+		t.Errorf("failed to find user by blind index, got %v, %v", result2.ID, err)
+	}
+
+	// re-encrypted with the primary key and blind index updated when saved
+	if err := DB.Model(&users[0]).Updates(EncryptedUser{Email: "changed@example.org"}).Error; err != nil {
+		t.Fatalf("failed to update user, got %v", err)
+	}
+	DB.Table("encrypted_users").Where("id = ?", oldUser.ID).Scan(&raw)
+	if !strings.HasPrefix(raw.Email, "v2:") {
+		t.Errorf("value should be re-encrypted with primary key v2, got %#v", raw)
+	}
+	AssertEqual(t, raw.EmailIndex, keyring.BlindIndex("changed@example.org"))
+
+	if err := DB.Model(&users[1]).Update("name", "new name").Error; err != nil {
+		t.Fatalf("failed to update user, got %v", err)
+	}
+	DB.Table("encrypted_users").Where("id = ?", user.ID).Scan(&raw)
+	AssertEqual(t, raw.EmailIndex, keyring.BlindIndex("new@example.org"))
+
+	// encrypted and blind index updated when updating with column or map
+	if err := DB.Model(&users[1]).Update("email", "column@example.org").Error; err != nil {
+		t.Fatalf("failed to update email, got %v", err)
+	}
+	DB.Table("encrypted_users").Where("id = ?", user.ID).Scan(&raw)
+	if !strings.HasPrefix(raw.Email, "v2:") || strings.Contains(raw.Email, "column@example.org") {
+		t.Errorf("value updated with column should be encrypted, got %#v", raw)
+	}
+	AssertEqual(t, raw.EmailIndex, keyring.BlindIndex("column@example.org"))
+
+	if err := DB.Model(&EncryptedUser{}).Where("id = ?", user.ID).Updates(map[string]interface{}{"email": "map@example.org"}).Error; err != nil {
+		t.Fatalf("failed to update email, got %v", err)
+	}
+
+	var result3 EncryptedUser
+	if err := DB.Where(&EncryptedUser{Email: "map@example.org"}, "EmailIndex").First(&result3).Error; err != nil || result3.ID != user.ID || result3.Email != "map@example.org" {
+		t.Errorf("failed to find user by blind index updated with map, got %v, %v", result3.ID, err)
+	}
+
+	if err := DB.Model(&users[1]).Update("email", gorm.Expr("email")).Error; err == nil {
+		t.Errorf("should failed to update blind index source with expression")
+	}
+
+	// tampered value can't be decrypted
+	DB.Table("encrypted_users").Where("id = ?", user.ID).Update("email", raw.Email[:len(raw.Email)-4]+"AAA=")
+	if err := DB.First(&EncryptedUser{}, user.ID).Error; err == nil {
+		t.Errorf("should failed to decrypt tampered value")
+	}
+
+	// values encrypted with unknown key can't be decrypted
+	DB.Table("encrypted_users").Where("id = ?", user.ID).Update("email", "v3:AAAA")
+	if err := DB.First(&EncryptedUser{}, user.ID).Error; err == nil {
+		t.Errorf("should failed to decrypt value encrypted with unknown key")
+	}
+}
+
+type CompressedDocument struct {
+	ID      uint
+	Content map[string]interface{} `gorm:"serializer:gzip+json;type:bytes"`
+	Tags    []string               `gorm:"serializer:flate+json;type:bytes"`
+	HTML    string                 `gorm:"serializer:gzip"`
+	Summary *string                `gorm:"serializer:gzip"`
+}
+
+func TestCompressedSerializer(t *testing.T) {
+	if _, ok := schema.GetSerializer("gzip+unknown"); ok {
+		t.Errorf("should not get serializer wrapping unknown serializer")
+	}
+	if _, ok := schema.GetSerializer("json+gzip"); ok {
+		t.Errorf("should not get serializer composed by non wrapper serializer")
+	}
+
+	DB.Migrator().DropTable(&CompressedDocument{})
+	if err := DB.AutoMigrate(&CompressedDocument{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	html := "<p>" + strings.Repeat("hello world ", 200) + "</p>"
+	tags := make([]string, 100)
+	for i := range tags {
+		tags[i] = fmt.Sprintf("tag-%d", i)
+	}
+	doc := CompressedDocument{
+		Content: map[string]interface{}{"title": "compressed", "body": strings.Repeat("lorem ipsum ", 100)},
+		Tags:    tags,
+		HTML:    html,
+	}
+	if err := DB.Create(&doc).Error; err != nil {
+		t.Fatalf("failed to create document, got %v", err)
+	}
+
+	var raw struct {
+		Content []byte
+		Tags    []byte
+		HTML    []byte
+		Summary []byte
+	}
+	DB.Table("compressed_documents").Where("id = ?", doc.ID).Scan(&raw)
+	// compressed values of text columns are base64 encoded
+	if !bytes.HasPrefix(raw.Content, []byte("\x00gzg")) || !bytes.HasPrefix(raw.Tags, []byte("\x00gzf")) || !bytes.HasPrefix(raw.HTML, []byte("\x01gzg")) || raw.Summary != nil {
+		t.Fatalf("values should be compressed, got %q", raw)
+	}
+	if len(raw.HTML) >= len(html) {
+		t.Errorf("compressed value should be smaller, got %v, original %v", len(raw.HTML), len(html))
+	}
+
+	var result CompressedDocument
+	if err := DB.First(&result, doc.ID).Error; err != nil {
+		t.Fatalf("failed to find document, got %v", err)
+	}
+	AssertEqual(t, result.Content, doc.Content)
+	AssertEqual(t, result.Tags, doc.Tags)
+	AssertEqual(t, result.HTML, html)
+	if result.Summary != nil {
+		t.Errorf("summary should be nil, got %v", *result.Summary)
+	}
+
+	// values smaller than threshold stored uncompressed
+	summary := "short summary"
+	if err := DB.Model(&result).Updates(CompressedDocument{Summary: &summary, Tags: []string{"small"}}).Error; err != nil {
+		t.Fatalf("failed to update document, got %v", err)
+	}
+	DB.Table("compressed_documents").Where("id = ?", doc.ID).Scan(&raw)
+	AssertEqual(t, string(raw.Summary), summary)
+	AssertEqual(t, string(raw.Tags), `["small"]`)
+
+	// uncompressed values keep the bind type of columns
+	stmt := &gorm.Statement{DB: DB}
+	stmt.Parse(&CompressedDocument{})
+	summaryField, tagsField := stmt.Schema.LookUpField("Summary"), stmt.Schema.LookUpField("Tags")
+	if value, err := summaryField.Serializer.Value(context.Background(), summaryField, reflect.ValueOf(result), &summary); err != nil || value != summary {
+		t.Errorf("uncompressed value of text column should be string, got %#v, %v", value, err)
+	}
+	if value, err := tagsField.Serializer.Value(context.Background(), tagsField, reflect.ValueOf(result), []string{"small"}); err != nil || !bytes.Equal(value.([]byte), []byte(`["small"]`)) {
+		t.Errorf("uncompressed value of bytes column should be []byte, got %#v, %v", value, err)
+	}
+
+	// legacy values stored before compression enabled
+	DB.Table("compressed_documents").Where("id = ?", doc.ID).Updates(map[string]interface{}{"content": `{"title":"legacy"}`, "html": "<p>legacy</p>"})
+
+	var result2 CompressedDocument
+	if err := DB.First(&result2, doc.ID).Error; err != nil {
+		t.Fatalf("failed to find document, got %v", err)
+	}
+	AssertEqual(t, result2.Content, map[string]interface{}{"title": "legacy"})
+	AssertEqual(t, result2.Tags, []string{"small"})
+	AssertEqual(t, result2.HTML, "<p>legacy</p>")
+	AssertEqual(t, *result2.Summary, summary)
+}
+
+type LegacyDocument struct {
+	ID      uint
+	Content map[string]interface{} `gorm:"serializer:json"`
+	HTML    string
+}
+
+type LegacyCompressedDocument struct {
+	ID      uint
+	Content map[string]interface{} `gorm:"serializer:gzip+json"`
+	HTML    string                 `gorm:"serializer:gzip"`
+}
+
+func (LegacyCompressedDocument) TableName() string { return "legacy_documents" }
+
+func TestCompressedSerializerMigration(t *testing.T) {
+	DB.Migrator().DropTable(&LegacyDocument{})
+	if err := DB.AutoMigrate(&LegacyDocument{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	legacy := LegacyDocument{Content: map[string]interface{}{"title": "legacy"}, HTML: "<p>legacy</p>"}
+	if err := DB.Create(&legacy).Error; err != nil {
+		t.Fatalf("failed to create document, got %v", err)
+	}
+
+	columnTypes := map[string]string{}
+	types, _ := DB.Migrator().ColumnTypes(&LegacyDocument{})
+	for _, columnType := range types {
+		columnTypes[columnType.Name()] = columnType.DatabaseTypeName()
+	}
+
+	// enable compression
+	if err := DB.AutoMigrate(&LegacyCompressedDocument{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	types, _ = DB.Migrator().ColumnTypes(&LegacyCompressedDocument{})
+	for _, columnType := range types {
+		if columnTypes[columnType.Name()] != columnType.DatabaseTypeName() {
+			t.Errorf("column %v should not be altered, expects %v, got %v", columnType.Name(), columnTypes[columnType.Name()], columnType.DatabaseTypeName())
+		}
+	}
+
+	var result LegacyCompressedDocument
+	if err := DB.First(&result, legacy.ID).Error; err != nil {
+		t.Fatalf("failed to find legacy document, got %v", err)
+	}
+	AssertEqual(t, result.Content, legacy.Content)
+	AssertEqual(t, result.HTML, legacy.HTML)
+
+	doc := LegacyCompressedDocument{
+		Content: map[string]interface{}{"body": strings.Repeat("lorem ipsum ", 100)},
+		HTML:    "<p>" + strings.Repeat("hello world ", 200) + "</p>",
+	}
+	if err := DB.Create(&doc).Error; err != nil {
+		t.Fatalf("failed to create compressed document, got %v", err)
+	}
+
+	var raw struct {
+		Content string
+		HTML    string
+	}
+	DB.Table("legacy_documents").Where("id = ?", doc.ID).Scan(&raw)
+	if !strings.HasPrefix(raw.Content, "\x01gzg") || !strings.HasPrefix(raw.HTML, "\x01gzg") {
+		t.Fatalf("values should be compressed into text, got %q", raw)
+	}
+
+	var result2 LegacyCompressedDocument
+	if err := DB.First(&result2, doc.ID).Error; err != nil {
+		t.Fatalf("failed to find compressed document, got %v", err)
+	}
+	AssertEqual(t, result2.Content, doc.Content)
+	AssertEqual(t, result2.HTML, doc.HTML)
+}
@@ -0,0 +1,155 @@
+package tests_test
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CascadeAuthor struct {
+	gorm.Model
+	Name  string
+	Books []CascadeBook `gorm:"onSoftDelete:cascade"`
+	Tags  []CascadeTag  `gorm:"many2many:cascade_author_tags;onSoftDelete:cascade"`
+}
+
+type CascadeBook struct {
+	gorm.Model
+	CascadeAuthorID uint
+	Title           string
+	Chapters        []CascadeChapter `gorm:"onSoftDelete:cascade"`
+}
+
+type CascadeChapter struct {
+	gorm.Model
+	CascadeBookID uint
+	Title         string
+}
+
+type CascadeTag struct {
+	ID   uint
+	Name string
+}
+
+type CascadeAuthorTag struct {
+	CascadeAuthorID uint `gorm:"primaryKey"`
+	CascadeTagID    uint `gorm:"primaryKey"`
+	DeletedAt       gorm.DeletedAt
+}
+
+func TestSoftDeleteCascade(t *testing.T) {
+	DB.Migrator().DropTable(&CascadeAuthor{}, &CascadeBook{}, &CascadeChapter{}, &CascadeTag{}, &CascadeAuthorTag{})
+	if err := DB.SetupJoinTable(&CascadeAuthor{}, "Tags", &CascadeAuthorTag{}); err != nil {
+		t.Fatalf("failed to setup join table, got error %v", err)
+	}
+	if err := DB.AutoMigrate(&CascadeAuthor{}, &CascadeBook{}, &CascadeChapter{}, &CascadeTag{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	author := CascadeAuthor{
+		Name: "cascade",
+		Books: []CascadeBook{
+			{Title: "book-1", Chapters: []CascadeChapter{{Title: "chapter-1-1"}, {Title: "chapter-1-2"}}},
+			{Title: "book-2", Chapters: []CascadeChapter{{Title: "chapter-2-1"}, {Title: "chapter-2-2"}}},
+		},
+		Tags: []CascadeTag{{Name: "tag-1"}},
+	}
+	if err := DB.Create(&author).Error; err != nil {
+		t.Fatalf("failed to create author, got error %v", err)
+	}
+
+	if err := DB.Delete(&author.Books[0]).Error; err != nil {
+		t.Fatalf("failed to delete book, got error %v", err)
+	}
+
+	var count int64
+	if DB.Model(&CascadeChapter{}).Count(&count); count != 2 {
+		t.Fatalf("chapters of deleted book should be soft deleted, got %v chapters", count)
+	}
+
+	if err := DB.Delete(&author).Error; err != nil {
+		t.Fatalf("failed to delete author, got error %v", err)
+	}
+
+	if DB.Model(&CascadeBook{}).Count(&count); count != 0 {
+		t.Errorf("books should be soft deleted, got %v", count)
+	}
+
+	if DB.Model(&CascadeChapter{}).Count(&count); count != 0 {
+		t.Errorf("chapters should be soft deleted, got %v", count)
+	}
+
+	if DB.Model(&CascadeAuthorTag{}).Count(&count); count != 0 {
+		t.Errorf("join table records should be soft deleted, got %v", count)
+	}
+
+	if DB.Model(&CascadeTag{}).Count(&count); count != 1 {
+		t.Errorf("tags should not be deleted, got %v", count)
+	}
+
+	var book CascadeBook
+	DB.Unscoped().First(&book, author.Books[1].ID)
+	if !book.DeletedAt.Time.Equal(author.DeletedAt.Time) {
+		t.Errorf("cascade soft deleted records should share the deletion time, got %v, %v", book.DeletedAt.Time, author.DeletedAt.Time)
+	}
+
+	if err := DB.Restore(&CascadeAuthor{}, author.ID).Error; err != nil {
+		t.Fatalf("failed to restore author, got error %v", err)
+	}
+
+	var books []CascadeBook
+	if DB.Preload("Chapters").Where("cascade_author_id = ?", author.ID).Find(&books); len(books) != 1 || books[0].Title != "book-2" {
+		t.Fatalf("should only restore the book deleted with author, got %#v", books)
+	}
+
+	if len(books[0].Chapters) != 2 {
+		t.Errorf("should restore chapters deleted with author, got %v", len(books[0].Chapters))
+	}
+
+	if DB.Model(&CascadeChapter{}).Count(&count); count != 2 {
+		t.Errorf("chapters of book deleted before should not be restored, got %v", count)
+	}
+
+	if DB.Model(&CascadeAuthorTag{}).Count(&count); count != 1 {
+		t.Errorf("join table records should be restored, got %v", count)
+	}
+
+	if err := DB.Omit("Books").Delete(&author).Error; err != nil {
+		t.Fatalf("failed to delete author, got error %v", err)
+	}
+
+	if DB.Model(&CascadeBook{}).Count(&count); count != 1 {
+		t.Errorf("omitted associations should not be soft deleted, got %v", count)
+	}
+
+	if DB.Model(&CascadeAuthorTag{}).Count(&count); count != 0 {
+		t.Errorf("join table records should be soft deleted, got %v", count)
+	}
+}
+
+func TestSoftDeleteCascadeWithReusedStatement(t *testing.T) {
+	authors := []CascadeAuthor{
+		{Name: "cascade-reused-1", Books: []CascadeBook{{Title: "reused-book-1"}}},
+		{Name: "cascade-reused-2", Books: []CascadeBook{{Title: "reused-book-2"}}},
+	}
+	if err := DB.Create(&authors).Error; err != nil {
+		t.Fatalf("failed to create authors, got error %v", err)
+	}
+
+	tx := DB.Where("name LIKE ?", "cascade-reused-%")
+	if err := tx.Delete(&authors[0]).Error; err != nil {
+		t.Fatalf("failed to delete author, got error %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if err := tx.Delete(&authors[1]).Error; err != nil {
+		t.Fatalf("failed to delete author, got error %v", err)
+	}
+
+	var books []CascadeBook
+	DB.Unscoped().Where("cascade_author_id IN ?", []uint{authors[0].ID, authors[1].ID}).Order("id").Find(&books)
+	if len(books) != 2 || books[0].DeletedAt.Time.Equal(books[1].DeletedAt.Time) {
+		t.Fatalf("deletions of reused statement should not share the deletion time, got %#v", books)
+	}
+}
@@ -0,0 +1,101 @@
+package tests_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UnixDeletedProduct struct {
+	ID        uint
+	Name      string
+	DeletedAt gorm.DeletedAtUnix `gorm:"softDelete:milli;deletedByField:DeletedBy"`
+	DeletedBy string
+}
+
+type FlagDeletedProduct struct {
+	ID        uint
+	Name      string
+	IsDeleted gorm.DeletedFlag `gorm:"deletedAtField:DeletedAt"`
+	DeletedAt *time.Time
+}
+
+func TestSoftDeleteUnix(t *testing.T) {
+	DB.Migrator().DropTable(&UnixDeletedProduct{})
+	if err := DB.AutoMigrate(&UnixDeletedProduct{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	products := []UnixDeletedProduct{{Name: "unix-1"}, {Name: "unix-2"}}
+	DB.Create(&products)
+
+	tx := DB.WithContext(gorm.WithActor(context.Background(), "jinzhu"))
+	if err := tx.Delete(&products[0]).Error; err != nil {
+		t.Fatalf("failed to soft delete, got error %v", err)
+	}
+
+	if products[0].DeletedAt == 0 || products[0].DeletedBy != "jinzhu" {
+		t.Errorf("deleted at and deleted by should be set, got %v, %v", products[0].DeletedAt, products[0].DeletedBy)
+	}
+
+	var count int64
+	if DB.Model(&UnixDeletedProduct{}).Count(&count); count != 1 {
+		t.Errorf("should find 1 product, got %v", count)
+	}
+
+	var deleted UnixDeletedProduct
+	if err := DB.Unscoped().First(&deleted, products[0].ID).Error; err != nil {
+		t.Fatalf("failed to find soft deleted product with Unscoped, got error %v", err)
+	}
+
+	if deleted.DeletedBy != "jinzhu" || deleted.DeletedAt < gorm.DeletedAtUnix(time.Now().Add(-time.Minute).UnixNano()/1e6) {
+		t.Errorf("invalid soft deleted product, got %#v", deleted)
+	}
+
+	if err := DB.Unscoped().Delete(&products[0]).Error; err != nil {
+		t.Fatalf("failed to permanently delete, got error %v", err)
+	}
+
+	if DB.Unscoped().Model(&UnixDeletedProduct{}).Count(&count); count != 1 {
+		t.Errorf("should find 1 product after permanently delete, got %v", count)
+	}
+}
+
+func TestSoftDeleteFlag(t *testing.T) {
+	DB.Migrator().DropTable(&FlagDeletedProduct{})
+	if err := DB.AutoMigrate(&FlagDeletedProduct{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	products := []FlagDeletedProduct{{Name: "flag-1"}, {Name: "flag-2"}, {Name: "flag-3"}}
+	DB.Create(&products)
+
+	if err := DB.Where("name IN ?", []string{"flag-1", "flag-2"}).Delete(&FlagDeletedProduct{}).Error; err != nil {
+		t.Fatalf("failed to soft delete, got error %v", err)
+	}
+
+	var result []FlagDeletedProduct
+	if DB.Find(&result); len(result) != 1 || result[0].Name != "flag-3" {
+		t.Errorf("should only find not deleted product, got %#v", result)
+	}
+
+	result = nil
+	if DB.Unscoped().Where("is_deleted = ?", true).Order("id").Find(&result); len(result) != 2 {
+		t.Fatalf("should find 2 deleted products with Unscoped, got %v", len(result))
+	}
+
+	if !result[0].IsDeleted || result[0].DeletedAt == nil {
+		t.Errorf("flag and deleted at should be set in mixed mode, got %#v", result[0])
+	}
+
+	if err := DB.Model(&FlagDeletedProduct{}).Where("name = ?", "flag-1").Update("name", "flag-1-updated").Error; err != nil {
+		t.Fatalf("failed to update, got error %v", err)
+	}
+
+	var updated FlagDeletedProduct
+	if DB.Unscoped().First(&updated, products[0].ID); updated.Name != "flag-1" {
+		t.Errorf("soft deleted product should not be updated, got %v", updated.Name)
+	}
+}
@@ -0,0 +1,247 @@
+package tests_test
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TreeCategory struct {
+	ID       uint
+	Name     string
+	ParentID *uint
+	Parent   *TreeCategory
+	Children []TreeCategory `gorm:"foreignKey:ParentID"`
+	Depth    int            `gorm:"-"`
+	Path     string         `gorm:"-"`
+}
+
+// cteDialector loads trees with recursive CTE of SQLite, Postgres
+type cteDialector struct {
+	gorm.Dialector
+}
+
+func (cteDialector) SupportRecursiveCTE() bool {
+	return true
+}
+
+func (cteDialector) RecursiveWith() string {
+	return "WITH RECURSIVE"
+}
+
+func (cteDialector) CastToText(expr clause.Expression) clause.Expression {
+	return clause.Expr{SQL: "CAST(? AS TEXT)", Vars: []interface{}{expr}}
+}
+
+func (cteDialector) Concat(exprs ...clause.Expression) clause.Expression {
+	vars := make([]interface{}, 0, len(exprs))
+	for _, expr := range exprs {
+		vars = append(vars, expr)
+	}
+	return clause.Expr{SQL: strings.TrimSuffix(strings.Repeat("? || ", len(exprs)), " || "), Vars: vars}
+}
+
+// levelDialector disables recursive CTE to load trees level by level
+type levelDialector struct {
+	cteDialector
+}
+
+func (levelDialector) SupportRecursiveCTE() bool {
+	return false
+}
+
+// treeDBs returns the DBs loading trees with recursive CTE if the SQL of cteDialector is supported, and level by level
+func treeDBs() map[string]*gorm.DB {
+	levelDB := DB.Session(&gorm.Session{})
+	levelDB.Dialector = levelDialector{cteDialector{Dialector: DB.Dialector}}
+	dbs := map[string]*gorm.DB{"level": levelDB}
+
+	switch DB.Dialector.Name() {
+	case "sqlite", "postgres":
+		cteDB := DB.Session(&gorm.Session{})
+		cteDB.Dialector = cteDialector{Dialector: DB.Dialector}
+		dbs["cte"] = cteDB
+	}
+	return dbs
+}
+
+func TestTree(t *testing.T) {
+	DB.Migrator().DropTable(&TreeCategory{})
+	if err := DB.AutoMigrate(&TreeCategory{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	root := TreeCategory{Name: "root", Children: []TreeCategory{
+		{Name: "a", Children: []TreeCategory{
+			{Name: "a1", Children: []TreeCategory{{Name: "a1x"}}},
+			{Name: "a2"},
+		}},
+		{Name: "b"},
+	}}
+	if err := DB.Create(&root).Error; err != nil {
+		t.Fatalf("failed to create tree, got error %v", err)
+	}
+
+	for name, db := range treeDBs() {
+		var category TreeCategory
+		DB.First(&category, root.ID)
+
+		result := db.Descendants(&category, "Children", gorm.TreeOption{DepthField: "Depth", PathField: "Path"})
+		if result.Error != nil {
+			t.Fatalf("%v: failed to load descendants, got error %v", name, result.Error)
+		}
+
+		if result.RowsAffected != 5 {
+			t.Errorf("%v: should load 5 descendants, got %v", name, result.RowsAffected)
+		}
+
+		if len(category.Children) != 2 || len(category.Children[0].Children) != 2 || len(category.Children[1].Children) != 0 {
+			t.Fatalf("%v: invalid tree, got %#v", name, category)
+		}
+
+		a1 := category.Children[0].Children[0]
+		if a1.Name != "a1" || len(a1.Children) != 1 || a1.Children[0].Name != "a1x" {
+			t.Fatalf("%v: invalid nested children, got %#v", name, a1)
+		}
+
+		a1x := a1.Children[0]
+		if a1x.Depth != 3 || a1.Depth != 2 || category.Depth != 0 {
+			t.Errorf("%v: invalid depth, got %v, %v, %v", name, category.Depth, a1.Depth, a1x.Depth)
+		}
+
+		if expects := "1/2/4/6"; a1x.Path != expects {
+			t.Errorf("%v: invalid path, expects %v, got %v", name, expects, a1x.Path)
+		}
+
+		category = TreeCategory{}
+		DB.First(&category, root.ID)
+		if err := db.Descendants(&category, "Children", gorm.TreeOption{MaxDepth: 2}).Error; err != nil {
+			t.Fatalf("%v: failed to load descendants, got error %v", name, err)
+		}
+
+		if len(category.Children) != 2 || len(category.Children[0].Children) != 2 || len(category.Children[0].Children[0].Children) != 0 {
+			t.Errorf("%v: descendants should be limited by max depth, got %#v", name, category)
+		}
+
+		var leaves []TreeCategory
+		DB.Where("name IN ?", []string{"a1x", "a2"}).Order("id DESC").Find(&leaves)
+		if err := db.Ancestors(&leaves, "Parent", gorm.TreeOption{DepthField: "Depth"}).Error; err != nil {
+			t.Fatalf("%v: failed to load ancestors, got error %v", name, err)
+		}
+
+		if parent := leaves[0].Parent; parent == nil || parent.Name != "a1" || parent.Parent == nil || parent.Parent.Name != "a" ||
+			parent.Parent.Parent == nil || parent.Parent.Parent.Name != "root" || parent.Parent.Parent.Parent != nil {
+			t.Fatalf("%v: invalid ancestors, got %#v", name, leaves[0].Parent)
+		}
+
+		// root is shared with a2, which is nearer
+		if leaves[0].Parent.Depth != 1 || leaves[0].Parent.Parent.Parent.Depth != 2 {
+			t.Errorf("%v: invalid depth of ancestors, got %v, %v", name, leaves[0].Parent.Depth, leaves[0].Parent.Parent.Parent.Depth)
+		}
+
+		if leaves[1].Parent == nil || leaves[1].Parent.Name != "a" || leaves[1].Parent.Parent == nil || leaves[1].Parent.Parent.Name != "root" {
+			t.Errorf("%v: invalid ancestors, got %#v", name, leaves[1].Parent)
+		}
+
+		category = TreeCategory{}
+		DB.First(&category, root.ID)
+		if err := db.Where("tree_categories.name <> ?", "a").Descendants(&category, "Children").Error; err != nil {
+			t.Fatalf("%v: failed to load descendants with conditions, got error %v", name, err)
+		}
+
+		if len(category.Children) != 1 || category.Children[0].Name != "b" {
+			t.Errorf("%v: descendants should be filtered by conditions, got %#v", name, category.Children)
+		}
+	}
+
+	if err := DB.Descendants(&TreeCategory{}, "Parent").Error; err == nil {
+		t.Errorf("should return error for non has many relation")
+	}
+}
+
+func TestTreeCyclic(t *testing.T) {
+	DB.Migrator().DropTable(&TreeCategory{})
+	if err := DB.AutoMigrate(&TreeCategory{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	x := TreeCategory{Name: "x", Children: []TreeCategory{{Name: "y", Children: []TreeCategory{{Name: "z"}}}}}
+	if err := DB.Create(&x).Error; err != nil {
+		t.Fatalf("failed to create tree, got error %v", err)
+	}
+	DB.Model(&x).Update("parent_id", x.Children[0].Children[0].ID)
+
+	for name, db := range treeDBs() {
+		var category TreeCategory
+		DB.First(&category, x.ID)
+
+		if err := db.Descendants(&category, "Children", gorm.TreeOption{DepthField: "Depth"}).Error; err != nil {
+			t.Fatalf("%v: failed to load cyclic descendants, got error %v", name, err)
+		}
+
+		if len(category.Children) != 1 || len(category.Children[0].Children) != 1 || len(category.Children[0].Children[0].Children) != 0 {
+			t.Errorf("%v: cyclic references should be skipped, got %#v", name, category)
+		}
+
+		var leaf TreeCategory
+		DB.First(&leaf, x.Children[0].Children[0].ID)
+		if err := db.Ancestors(&leaf, "Parent").Error; err != nil {
+			t.Fatalf("%v: failed to load cyclic ancestors, got error %v", name, err)
+		}
+
+		if leaf.Parent == nil || leaf.Parent.Name != "y" || leaf.Parent.Parent == nil || leaf.Parent.Parent.Name != "x" || leaf.Parent.Parent.Parent != nil {
+			t.Errorf("%v: invalid cyclic ancestors, got %#v", name, leaf.Parent)
+		}
+	}
+}
+
+type TreeNode struct {
+	Key       string `gorm:"primaryKey;size:64"`
+	ParentKey *string
+	Children  []TreeNode `gorm:"foreignKey:ParentKey"`
+	Path      string     `gorm:"-"`
+}
+
+func TestTreeStringKeys(t *testing.T) {
+	DB.Migrator().DropTable(&TreeNode{})
+	if err := DB.AutoMigrate(&TreeNode{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	// keys contain the separators of path and the wildcards of LIKE
+	keys := []string{"a/b", "a", "b", "x1", "x_", "a,b%"}
+	for idx, key := range keys {
+		node := TreeNode{Key: key}
+		if idx > 0 {
+			node.ParentKey = &keys[idx-1]
+		}
+		if err := DB.Create(&node).Error; err != nil {
+			t.Fatalf("failed to create node, got error %v", err)
+		}
+	}
+	DB.Model(&TreeNode{Key: keys[0]}).Update("parent_key", keys[len(keys)-1])
+
+	for name, db := range treeDBs() {
+		root := TreeNode{Key: keys[0]}
+		result := db.Descendants(&root, "Children", gorm.TreeOption{PathField: "Path"})
+		if result.Error != nil {
+			t.Fatalf("%v: failed to load descendants, got error %v", name, result.Error)
+		}
+
+		// the root is loaded as the child of the last node, but not expanded again
+		if result.RowsAffected != 6 {
+			t.Errorf("%v: should load 6 nodes, got %v", name, result.RowsAffected)
+		}
+
+		node, depth := root, 0
+		for ; len(node.Children) == 1; depth++ {
+			node = node.Children[0]
+		}
+
+		if depth != 5 || node.Key != "a,b%" || node.Path != strings.Join(keys, "/") {
+			t.Errorf("%v: invalid descendants, got depth %v, node %#v", name, depth, node)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UowCustomer struct {
+	gorm.ChangeTracker
+	ID     uint
+	Name   string
+	Orders []UowOrder
+}
+
+type UowOrder struct {
+	ID            uint
+	UowCustomerID uint
+	Amount        int
+}
+
+func TestUnitOfWork(t *testing.T) {
+	DB.Migrator().DropTable(&UowOrder{}, &UowCustomer{})
+	if err := DB.AutoMigrate(&UowCustomer{}, &UowOrder{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	uow := DB.UnitOfWork()
+	customer := &UowCustomer{ID: 100, Name: "uow"}
+	orders := []*UowOrder{{UowCustomerID: 100, Amount: 10}, {UowCustomerID: 100, Amount: 20}}
+
+	// orders are registered before customer, but should be created after it
+	if err := uow.RegisterNew(orders[0], orders[1], customer); err != nil {
+		t.Fatalf("failed to register entities, got error %v", err)
+	}
+
+	if err := uow.Commit(); err != nil {
+		t.Fatalf("failed to commit, got error %v", err)
+	}
+
+	if orders[0].ID == 0 || orders[1].ID == 0 {
+		t.Errorf("created orders should have primary key, got %v, %v", orders[0].ID, orders[1].ID)
+	}
+
+	var c1, c2 *UowCustomer
+	if err := uow.First(&c1, 100); err != nil {
+		t.Fatalf("failed to find customer, got error %v", err)
+	}
+	if err := uow.First(&c2, "name = ?", "uow"); err != nil {
+		t.Fatalf("failed to find customer, got error %v", err)
+	}
+
+	if c1 != customer || c2 != customer {
+		t.Errorf("should return the same pointer from identity map")
+	}
+
+	var found []*UowOrder
+	if err := uow.Find(&found, "uow_customer_id = ?", 100); err != nil || len(found) != 2 {
+		t.Fatalf("failed to find orders, got %v, %v", len(found), err)
+	}
+
+	if found[0] != orders[0] && found[0] != orders[1] {
+		t.Errorf("should return the same pointer for created entities")
+	}
+
+	if err := uow.RegisterNew(UowOrder{}); !errors.Is(err, gorm.ErrInvalidValue) {
+		t.Errorf("should return invalid value error, got %v", err)
+	}
+
+	// trackable entities loaded before are updated automatically
+	customer.Name = "uow-updated"
+	orders[0].Amount = 0
+	uow.RegisterDirty(orders[0])
+
+	// customer is registered before orders, but should be deleted after them
+	newCustomer := &UowCustomer{ID: 101, Name: "uow-2"}
+	newOrder := &UowOrder{UowCustomerID: 101, Amount: 30}
+	DB.Create(newCustomer)
+	DB.Create(newOrder)
+	uow.RegisterDeleted(newCustomer, newOrder)
+
+	discarded := &UowOrder{UowCustomerID: 100, Amount: 40}
+	uow.RegisterNew(discarded)
+	uow.RegisterDeleted(discarded)
+
+	if err := uow.Commit(); err != nil {
+		t.Fatalf("failed to commit, got error %v", err)
+	}
+
+	var result UowCustomer
+	if DB.First(&result, 100); result.Name != "uow-updated" {
+		t.Errorf("customer should be updated, got %v", result.Name)
+	}
+
+	var order UowOrder
+	if DB.First(&order, orders[0].ID); order.Amount != 0 {
+		t.Errorf("order should be updated, got %v", order.Amount)
+	}
+
+	var count int64
+	if DB.Model(&UowCustomer{}).Where("id = ?", 101).Count(&count); count != 0 {
+		t.Errorf("customer should be deleted, got %v", count)
+	}
+
+	if DB.Model(&UowOrder{}).Count(&count); count != 2 {
+		t.Errorf("discarded order should not be created, got %v orders", count)
+	}
+
+	uow.RegisterNew(&UowOrder{ID: orders[0].ID, UowCustomerID: 100, Amount: 50})
+	customer.Name = "uow-rollback"
+	if err := uow.Commit(); err == nil {
+		t.Fatalf("should fail to create order with duplicated primary key")
+	}
+
+	if DB.First(&result, 100); result.Name != "uow-updated" {
+		t.Errorf("changes should be rolled back, got %v", result.Name)
+	}
+}
+
+type UowHookedCustomer struct {
+	ID         uint
+	Name       string
+	UnitOfWork *gorm.UnitOfWork `gorm:"-"`
+}
+
+// AfterCreate calls back into the unit of work while committing
+func (c *UowHookedCustomer) AfterCreate(tx *gorm.DB) error {
+	var customers []*UowHookedCustomer
+	if err := c.UnitOfWork.Find(&customers); err != nil {
+		return err
+	}
+	return c.UnitOfWork.RegisterNew(&UowOrder{UowCustomerID: c.ID, Amount: len(customers)})
+}
+
+func TestUnitOfWorkHooks(t *testing.T) {
+	DB.Migrator().DropTable(&UowOrder{}, &UowHookedCustomer{})
+	if err := DB.AutoMigrate(&UowHookedCustomer{}, &UowOrder{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	uow := DB.UnitOfWork()
+	customer := &UowHookedCustomer{ID: 100, Name: "uow-hooked", UnitOfWork: uow}
+	uow.RegisterNew(customer)
+
+	done := make(chan error, 1)
+	go func() { done <- uow.Commit() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("failed to commit, got error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("hooks calling back into the unit of work should not deadlock")
+	}
+
+	var count int64
+	if DB.Model(&UowOrder{}).Count(&count); count != 0 {
+		t.Errorf("order registered by hooks should be committed next time, got %v orders", count)
+	}
+
+	if err := uow.Commit(); err != nil {
+		t.Fatalf("failed to commit, got error %v", err)
+	}
+
+	if DB.Model(&UowOrder{}).Where("uow_customer_id = ?", customer.ID).Count(&count); count != 1 {
+		t.Errorf("order registered by hooks should be created, got %v orders", count)
+	}
+}
@@ -0,0 +1,151 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type ValidatedUser struct {
+	ID       uint
+	Name     string  `validate:"required,max=16"`
+	Email    string  `validate:"omitempty,email"`
+	Role     string  `gorm:"default:member" validate:"oneof=admin|member"`
+	Age      int     `validate:"min=0,max=150"`
+	Nickname *string `validate:"omitempty,min=2"`
+}
+
+func validationDB() *gorm.DB {
+	db := DB.Session(&gorm.Session{})
+	db.Config.Validator = gorm.DefaultValidator{}
+	return db
+}
+
+func assertValidationError(t *testing.T, err error, fields ...string) {
+	t.Helper()
+
+	var validationErr *gorm.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("should returns validation error, got %v", err)
+	}
+	AssertEqual(t, validationErr.Fields(), fields)
+}
+
+func TestValidateCreate(t *testing.T) {
+	DB.Migrator().DropTable(&ValidatedUser{})
+	if err := DB.AutoMigrate(&ValidatedUser{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+	db := validationDB()
+
+	if err := DB.Create(&ValidatedUser{Role: "guest"}).Error; err != nil {
+		t.Errorf("should not validate without validator, got %v", err)
+	}
+
+	nickname := "x"
+	err := db.Create(&ValidatedUser{Name: strings.Repeat("a", 17), Email: "invalid", Role: "guest", Age: -1, Nickname: &nickname}).Error
+	assertValidationError(t, err, "Name", "Email", "Role", "Age", "Nickname")
+
+	var validationErr *gorm.ValidationError
+	errors.As(err, &validationErr)
+	AssertEqual(t, validationErr.Errors[0].DBName, "name")
+	if !strings.Contains(err.Error(), "Email (email) must be a valid email address") {
+		t.Errorf("error should contain field and message, got %v", err)
+	}
+
+	var count int64
+	DB.Model(&ValidatedUser{}).Where("role = ?", "admin").Count(&count)
+	AssertEqual(t, count, 0)
+
+	if err := db.Create(&ValidatedUser{Name: "jinzhu", Email: "jinzhu@example.org", Role: "admin", Age: 18}).Error; err != nil {
+		t.Errorf("failed to create valid user, got %v", err)
+	}
+
+	users := []ValidatedUser{{Name: "valid", Role: "member"}, {Role: "member"}}
+	assertValidationError(t, db.Create(&users).Error, "Name")
+
+	// omitted fields are not validated
+	if err := db.Omit("Role").Create(&ValidatedUser{Name: "omitted"}).Error; err != nil {
+		t.Errorf("omitted fields should not be validated, got %v", err)
+	}
+
+	if err := db.Select("Name").Create(&ValidatedUser{Name: "selected", Email: "invalid"}).Error; err != nil {
+		t.Errorf("unselected fields should not be validated, got %v", err)
+	}
+
+	assertValidationError(t, db.Model(&ValidatedUser{}).Create(map[string]interface{}{"Name": "map", "Email": "invalid"}).Error, "Email")
+}
+
+func TestValidateUpdate(t *testing.T) {
+	DB.Migrator().DropTable(&ValidatedUser{})
+	if err := DB.AutoMigrate(&ValidatedUser{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+	db := validationDB()
+
+	user := ValidatedUser{Name: "jinzhu", Role: "admin"}
+	db.Create(&user)
+
+	// only non-zero fields are written and validated
+	if err := db.Model(&user).Updates(ValidatedUser{Age: 20}).Error; err != nil {
+		t.Errorf("failed to update, got %v", err)
+	}
+
+	assertValidationError(t, db.Model(&user).Updates(ValidatedUser{Age: 200, Email: "invalid"}).Error, "Email", "Age")
+	assertValidationError(t, db.Model(&user).Select("Name", "Age").Updates(ValidatedUser{Age: 20}).Error, "Name")
+
+	assertValidationError(t, db.Model(&user).Updates(map[string]interface{}{"name": "", "age": 20}).Error, "Name")
+	if err := db.Model(&user).Updates(map[string]interface{}{"email": "jinzhu@example.org", "age": gorm.Expr("age + 1")}).Error; err != nil {
+		t.Errorf("failed to update with map, got %v", err)
+	}
+
+	assertValidationError(t, db.Model(&user).Update("role", "guest").Error, "Role")
+	if err := db.Model(&user).Omit("role").Updates(map[string]interface{}{"role": "guest", "age": 30}).Error; err != nil {
+		t.Errorf("omitted fields should not be validated, got %v", err)
+	}
+
+	var result ValidatedUser
+	DB.First(&result, user.ID)
+	AssertEqual(t, result.Role, "admin")
+	AssertEqual(t, result.Age, 30)
+	AssertEqual(t, result.Email, "jinzhu@example.org")
+
+	result.Name = ""
+	assertValidationError(t, db.Save(&result).Error, "Name")
+}
+
+type lowercaseValidator struct{}
+
+func (lowercaseValidator) Validate(ctx context.Context, field *schema.Field, value interface{}) error {
+	if s, ok := value.(string); ok && field.Tag.Get("validate") == "lowercase" && strings.ToLower(s) != s {
+		return errors.New("should be lowercase")
+	}
+	return nil
+}
+
+type CustomValidatedUser struct {
+	ID   uint
+	Code string `validate:"lowercase"`
+}
+
+func TestCustomValidator(t *testing.T) {
+	DB.Migrator().DropTable(&CustomValidatedUser{})
+	DB.AutoMigrate(&CustomValidatedUser{})
+
+	db := DB.Session(&gorm.Session{})
+	db.Config.Validator = lowercaseValidator{}
+
+	assertValidationError(t, db.Create(&CustomValidatedUser{Code: "ABC"}).Error, "Code")
+	if err := db.Create(&CustomValidatedUser{Code: "abc"}).Error; err != nil {
+		t.Errorf("failed to create, got %v", err)
+	}
+
+	if err := validationDB().Create(&CustomValidatedUser{Code: "abc"}).Error; err == nil || !strings.Contains(err.Error(), "unsupported validation rule lowercase") {
+		t.Errorf("should returns error for unsupported rule, got %v", err)
+	}
+}
@@ -0,0 +1,389 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
+)
+
+// TreeOption options to load tree with Descendants, Ancestors
+type TreeOption struct {
+	// MaxDepth max depth of the loaded nodes, no limit if zero
+	MaxDepth int
+	// DepthField field to save the depth of the node, the depth of the value is 0, its children or parent is 1,
+	// nodes shared by multiple values (e.g: ancestors of siblings) get the smallest depth
+	DepthField string
+	// PathField field to save the path of the node, primary keys from the value to the node joined with "/", e.g: 1/3/7
+	PathField string
+}
+
+var treeTable = clause.Table{Name: "gorm_tree"}
+
+// Descendants loads all descendants of value with self-referential has many or has one relation name,
+// assembles them into nested struct, conditions should be qualified with table name.
+// Trees are loaded with recursive CTE if supported, otherwise level by level, both stop at cyclic data
+//
+//	// type Category struct {
+//	//   ID       uint
+//	//   ParentID *uint
+//	//   Children []Category `gorm:"foreignKey:ParentID"`
+//	// }
+//	db.Descendants(&category, "Children", gorm.TreeOption{MaxDepth: 3})
+func (db *DB) Descendants(value interface{}, name string, opts ...TreeOption) (tx *DB) {
+	return db.loadTree(value, name, false, opts)
+}
+
+// Ancestors loads all ancestors of value with self-referential belongs to relation name,
+// assembles them into nested struct, conditions should be qualified with table name
+//
+//	// type Category struct {
+//	//   ID       uint
+//	//   ParentID *uint
+//	//   Parent   *Category
+//	// }
+//	db.Ancestors(&category, "Parent")
+func (db *DB) Ancestors(value interface{}, name string, opts ...TreeOption) (tx *DB) {
+	return db.loadTree(value, name, true, opts)
+}
+
+func (db *DB) loadTree(value interface{}, name string, ancestors bool, opts []TreeOption) (tx *DB) {
+	tx = db.getInstance()
+
+	var opt TreeOption
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	if err := tx.Statement.Parse(value); err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	var (
+		ctx          = tx.Statement.Context
+		s            = tx.Statement.Schema
+		rel          = s.Relationships.Relations[name]
+		depthField   *schema.Field
+		pathField    *schema.Field
+		ownFields    []*schema.Field
+		relFields    []*schema.Field
+		relKeys      []string
+		ownKeys      []string
+		relConds     []clause.Expression
+		reflectValue = reflect.Indirect(reflect.ValueOf(value))
+	)
+
+	if len(s.PrimaryFields) == 0 {
+		tx.AddError(fmt.Errorf("%w for tree of schema %s", ErrPrimaryKeyRequired, s.Name))
+		return
+	}
+
+	if rel == nil || rel.FieldSchema != s || rel.JoinTable != nil ||
+		(ancestors && rel.Type != schema.BelongsTo) || (!ancestors && rel.Type != schema.HasMany && rel.Type != schema.HasOne) {
+		tx.AddError(fmt.Errorf("%s: %w for tree of schema %s", name, ErrUnsupportedRelation, s.Name))
+		return
+	}
+
+	if opt.DepthField != "" {
+		if depthField = s.LookUpField(opt.DepthField); depthField == nil {
+			tx.AddError(fmt.Errorf("%s: %w for schema %s", opt.DepthField, ErrInvalidField, s.Name))
+			return
+		}
+	}
+
+	if opt.PathField != "" {
+		if pathField = s.LookUpField(opt.PathField); pathField == nil {
+			tx.AddError(fmt.Errorf("%s: %w for schema %s", opt.PathField, ErrInvalidField, s.Name))
+			return
+		}
+	}
+
+	for _, ref := range rel.References {
+		if ref.PrimaryValue != "" {
+			relConds = append(relConds, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: ref.ForeignKey.DBName}, Value: ref.PrimaryValue})
+		} else if ref.OwnPrimaryKey {
+			ownFields = append(ownFields, ref.PrimaryKey)
+			relFields = append(relFields, ref.ForeignKey)
+		} else {
+			ownFields = append(ownFields, ref.ForeignKey)
+			relFields = append(relFields, ref.PrimaryKey)
+		}
+	}
+
+	for idx, field := range ownFields {
+		ownKeys = append(ownKeys, field.DBName)
+		relKeys = append(relKeys, relFields[idx].DBName)
+	}
+
+	_, rootValues := schema.GetIdentityFieldValuesMap(ctx, reflectValue, ownFields)
+	nodes := s.MakeSlice().Elem()
+	if len(rootValues) > 0 {
+		var (
+			modelValue     = reflect.New(s.ModelType).Interface()
+			queryTx        = tx.Session(&Session{}).Model(modelValue).Session(&Session{})
+			column, values = schema.ToQueryValues(clause.CurrentTable, relKeys, rootValues)
+		)
+
+		if cte, ok := recursiveCTEDialectorOf(tx.Dialector); ok {
+			var (
+				depthColumn = clause.Column{Table: treeTable.Name, Name: "gorm_depth"}
+				pathColumn  = clause.Column{Table: treeTable.Name, Name: "gorm_path"}
+				joinConds   = make([]clause.Expression, 0, len(relKeys))
+				keyExprs    = make([]clause.Expression, 0, len(s.PrimaryFields)*2+1)
+			)
+
+			for idx, key := range relKeys {
+				joinConds = append(joinConds, clause.Eq{
+					Column: clause.Column{Table: clause.CurrentTable, Name: key},
+					Value:  clause.Column{Table: treeTable.Name, Name: ownKeys[idx]},
+				})
+			}
+
+			// escaped primary keys of the loaded nodes are saved into path like `/1/3/`, the nodes in the path are not
+			// loaded again, so the recursion stops at cyclic data
+			for idx, field := range s.PrimaryFields {
+				if idx > 0 {
+					keyExprs = append(keyExprs, clause.Expr{SQL: "','"})
+				}
+				keyExprs = append(keyExprs, escapeTreeKey(cte.CastToText(clause.Expr{SQL: "?", Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: field.DBName}}})))
+			}
+			nodeKey := cte.Concat(append(keyExprs, clause.Expr{SQL: "'/'"})...)
+
+			anchorTx := queryTx.Select("?.*, ? AS ?, ? AS ?", clause.Table{Name: clause.CurrentTable}, 1, clause.Column{Name: depthColumn.Name}, cte.Concat(clause.Expr{SQL: "'/'"}, nodeKey), clause.Column{Name: pathColumn.Name}).
+				Where(clause.IN{Column: column, Values: values})
+			recursiveTx := queryTx.Select("?.*, ? + 1, ?", clause.Table{Name: clause.CurrentTable}, depthColumn, cte.Concat(clause.Expr{SQL: "?", Vars: []interface{}{pathColumn}}, nodeKey)).
+				Joins("INNER JOIN ? ON ?", treeTable, clause.And(joinConds...)).
+				Where("? NOT LIKE ?", pathColumn, cte.Concat(clause.Expr{SQL: "'%/'"}, nodeKey, clause.Expr{SQL: "'%'"}))
+			if len(relConds) > 0 {
+				anchorTx = anchorTx.Where(clause.And(relConds...))
+				recursiveTx = recursiveTx.Where(clause.And(relConds...))
+			}
+			if opt.MaxDepth > 0 {
+				recursiveTx = recursiveTx.Where(clause.Lt{Column: depthColumn, Value: opt.MaxDepth})
+			}
+
+			result := tx.Session(&Session{NewDB: true}).Raw(cte.RecursiveWith()+" ? AS (? UNION ALL ?) SELECT * FROM ?", treeTable, anchorTx, recursiveTx, treeTable).
+				Scan(nodes.Addr().Interface())
+			if result.Error != nil {
+				tx.AddError(result.Error)
+				return
+			}
+		} else {
+			// load trees level by level, the loaded records are skipped to stop at cyclic data
+			loaded := map[string]bool{}
+			for depth := 1; len(values) > 0 && (opt.MaxDepth == 0 || depth <= opt.MaxDepth); depth++ {
+				results := s.MakeSlice().Elem()
+				levelTx := queryTx.Where(clause.IN{Column: column, Values: values})
+				if len(relConds) > 0 {
+					levelTx = levelTx.Where(clause.And(relConds...))
+				}
+
+				if err := levelTx.Find(results.Addr().Interface()).Error; err != nil {
+					tx.AddError(err)
+					return
+				}
+
+				levelValue := s.MakeSlice().Elem()
+				for i := 0; i < results.Len(); i++ {
+					primaryValues := make([]interface{}, 0, len(s.PrimaryFields))
+					for _, field := range s.PrimaryFields {
+						v, _ := field.ValueOf(ctx, results.Index(i))
+						primaryValues = append(primaryValues, v)
+					}
+
+					if key := utils.ToStringKey(primaryValues...); !loaded[key] {
+						loaded[key] = true
+						levelValue = reflect.Append(levelValue, results.Index(i))
+					}
+				}
+
+				nodes = reflect.AppendSlice(nodes, levelValue)
+				_, levelValues := schema.GetIdentityFieldValuesMap(ctx, levelValue, ownFields)
+				column, values = schema.ToQueryValues(clause.CurrentTable, relKeys, levelValues)
+			}
+		}
+	}
+
+	tx.RowsAffected = int64(nodes.Len())
+	tx.assembleTree(rel, reflectValue, nodes, ownFields, relFields, depthField, pathField)
+	return tx
+}
+
+// assembleTree assigns the loaded nodes into their parents level by level from the roots,
+// the nodes have been assigned before are not expanded again, so it is safe for cyclic data
+func (db *DB) assembleTree(rel *schema.Relationship, reflectValue, nodes reflect.Value, ownFields, relFields []*schema.Field, depthField, pathField *schema.Field) {
+	type treeNode struct {
+		value reflect.Value
+		path  string
+		keys  []string // identities of the nodes from the root to the node
+	}
+
+	type treeEdge struct {
+		parent, child reflect.Value
+	}
+
+	var (
+		ctx      = db.Statement.Context
+		s        = db.Statement.Schema
+		children = map[string][]reflect.Value{}
+		visited  = map[string]bool{}
+		current  []treeNode
+		levels   [][]treeEdge
+	)
+
+	keyOf := func(fields []*schema.Field, rv reflect.Value) (string, bool) {
+		values := make([]interface{}, 0, len(fields))
+		notZero := false
+		for _, field := range fields {
+			v, isZero := field.ValueOf(ctx, rv)
+			values = append(values, v)
+			notZero = notZero || !isZero
+		}
+		return utils.ToStringKey(values...), notZero
+	}
+
+	primaryKeyOf := func(rv reflect.Value) string {
+		values := make([]string, 0, len(s.PrimaryFields))
+		for _, field := range s.PrimaryFields {
+			v, _ := field.ValueOf(ctx, rv)
+			values = append(values, utils.ToString(v))
+		}
+		return strings.Join(values, ",")
+	}
+
+	// identityOf quotes primary keys, so keys containing separators are not mixed up
+	identityOf := func(rv reflect.Value) string {
+		values := make([]string, 0, len(s.PrimaryFields))
+		for _, field := range s.PrimaryFields {
+			v, _ := field.ValueOf(ctx, rv)
+			values = append(values, strconv.Quote(fmt.Sprint(v)))
+		}
+		return strings.Join(values, ",")
+	}
+
+	setNode := func(node treeNode, depth int) {
+		if depthField != nil {
+			db.AddError(depthField.Set(ctx, node.value, depth))
+		}
+		if pathField != nil {
+			db.AddError(pathField.Set(ctx, node.value, node.path))
+		}
+	}
+
+	addRoot := func(rv reflect.Value) {
+		if reflect.Indirect(rv).Kind() != reflect.Struct {
+			return
+		}
+
+		switch rel.Type {
+		case schema.HasMany:
+			db.AddError(rel.Field.Set(ctx, rv, reflect.MakeSlice(rel.Field.IndirectFieldType, 0, 10).Interface()))
+		default:
+			db.AddError(rel.Field.Set(ctx, rv, reflect.New(rel.Field.FieldType).Interface()))
+		}
+
+		node := treeNode{value: rv, path: primaryKeyOf(rv), keys: []string{identityOf(rv)}}
+		visited[node.keys[0]] = true
+		setNode(node, 0)
+		current = append(current, node)
+	}
+
+	switch reflectValue.Kind() {
+	case reflect.Struct:
+		addRoot(reflectValue)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < reflectValue.Len(); i++ {
+			addRoot(reflectValue.Index(i))
+		}
+	}
+
+	// recursive CTE returns shared nodes multiple times, e.g: ancestors of siblings
+	loaded := map[string]bool{}
+	for i := 0; i < nodes.Len(); i++ {
+		if identity := identityOf(nodes.Index(i)); !loaded[identity] {
+			loaded[identity] = true
+			if key, ok := keyOf(relFields, nodes.Index(i)); ok {
+				children[key] = append(children[key], nodes.Index(i))
+			}
+		}
+	}
+
+	for depth := 1; len(current) > 0; depth++ {
+		var (
+			next  []treeNode
+			edges []treeEdge
+		)
+
+		for _, parent := range current {
+			key, ok := keyOf(ownFields, parent.value)
+			if !ok {
+				continue
+			}
+
+			for _, child := range children[key] {
+				identity := identityOf(child)
+				if visited[identity] {
+					// shared by parents, e.g: ancestors of siblings, skip cyclic references
+					if !utils.Contains(parent.keys, identity) {
+						edges = append(edges, treeEdge{parent: parent.value, child: child})
+					}
+					continue
+				}
+
+				keys := make([]string, len(parent.keys), len(parent.keys)+1)
+				copy(keys, parent.keys)
+				node := treeNode{value: child, path: parent.path + "/" + primaryKeyOf(child), keys: append(keys, identity)}
+				visited[identity] = true
+				setNode(node, depth)
+				next = append(next, node)
+				edges = append(edges, treeEdge{parent: parent.value, child: child})
+			}
+		}
+
+		levels = append(levels, edges)
+		current = next
+	}
+
+	// assign from the deepest level, so the copied values of non-pointer fields contain their nested nodes
+	for i := len(levels) - 1; i >= 0; i-- {
+		for _, edge := range levels[i] {
+			fieldValue := rel.Field.ReflectValueOf(ctx, edge.parent)
+			if fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil() {
+				fieldValue.Set(reflect.New(rel.Field.FieldType.Elem()))
+			}
+
+			fieldValue = reflect.Indirect(fieldValue)
+			switch fieldValue.Kind() {
+			case reflect.Struct:
+				db.AddError(rel.Field.Set(ctx, edge.parent, edge.child.Interface()))
+			case reflect.Slice, reflect.Array:
+				if fieldValue.Type().Elem().Kind() == reflect.Ptr {
+					db.AddError(rel.Field.Set(ctx, edge.parent, reflect.Append(fieldValue, edge.child).Interface()))
+				} else {
+					db.AddError(rel.Field.Set(ctx, edge.parent, reflect.Append(fieldValue, edge.child.Elem()).Interface()))
+				}
+			}
+		}
+	}
+}
+
+// recursiveCTEDialectorOf returns the dialector if it supports recursive CTE, see RecursiveCTEDialectorInterface
+func recursiveCTEDialectorOf(dialector Dialector) (RecursiveCTEDialectorInterface, bool) {
+	if d, ok := dialector.(RecursiveCTEDialectorInterface); ok && d.SupportRecursiveCTE() {
+		return d, true
+	}
+	return nil, false
+}
+
+// escapeTreeKey escapes the separators of path and the wildcards of LIKE in key, e.g: `a/b` to `a!sb`
+func escapeTreeKey(key clause.Expression) clause.Expression {
+	for _, replacement := range [][2]string{{"!", "!!"}, {"/", "!s"}, {",", "!c"}, {"%", "!p"}, {"_", "!u"}} {
+		key = clause.Expr{SQL: fmt.Sprintf("REPLACE(?, '%s', '%s')", replacement[0], replacement[1]), Vars: []interface{}{key}}
+	}
+	return key
+}
@@ -0,0 +1,353 @@
+package gorm
+
+import (
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
+)
+
+// UnitOfWork tracks new, dirty and deleted entities, and persists them in dependency order within one transaction on Commit.
+// Entities loaded with First, Find are kept in an identity map, loading the same record again returns the same pointer
+//
+//	uow := db.UnitOfWork()
+//	var user *User
+//	uow.First(&user, 1)
+//	user.Name = "jinzhu"
+//	uow.RegisterDirty(user)
+//	uow.RegisterNew(&Pet{UserID: &user.ID, Name: "pet"})
+//	uow.Commit()
+type UnitOfWork struct {
+	db       *DB
+	mux      sync.Mutex
+	identity map[*schema.Schema]map[string]interface{}
+	news     []unitOfWorkEntity
+	dirties  []unitOfWorkEntity
+	deletes  []unitOfWorkEntity
+}
+
+type unitOfWorkEntity struct {
+	schema *schema.Schema
+	value  interface{}
+}
+
+// UnitOfWork returns a new unit of work
+func (db *DB) UnitOfWork() *UnitOfWork {
+	return &UnitOfWork{db: db.Session(&Session{NewDB: true}), identity: map[*schema.Schema]map[string]interface{}{}}
+}
+
+// RegisterNew registers entities to be created
+func (uow *UnitOfWork) RegisterNew(values ...interface{}) error {
+	return uow.register(&uow.news, values)
+}
+
+// RegisterDirty registers entities to be updated
+func (uow *UnitOfWork) RegisterDirty(values ...interface{}) error {
+	return uow.register(&uow.dirties, values)
+}
+
+// RegisterDeleted registers entities to be deleted, entities registered as new are discarded
+func (uow *UnitOfWork) RegisterDeleted(values ...interface{}) error {
+	uow.mux.Lock()
+	defer uow.mux.Unlock()
+
+	for _, value := range values {
+		entity, err := uow.entity(value)
+		if err != nil {
+			return err
+		}
+
+		if idx := indexOfEntity(uow.news, value); idx >= 0 {
+			uow.news = append(uow.news[:idx], uow.news[idx+1:]...)
+			continue
+		}
+
+		if idx := indexOfEntity(uow.dirties, value); idx >= 0 {
+			uow.dirties = append(uow.dirties[:idx], uow.dirties[idx+1:]...)
+		}
+
+		if indexOfEntity(uow.deletes, value) < 0 {
+			uow.deletes = append(uow.deletes, entity)
+		}
+	}
+	return nil
+}
+
+func (uow *UnitOfWork) register(entities *[]unitOfWorkEntity, values []interface{}) error {
+	uow.mux.Lock()
+	defer uow.mux.Unlock()
+
+	for _, value := range values {
+		entity, err := uow.entity(value)
+		if err != nil {
+			return err
+		}
+
+		if indexOfEntity(uow.news, value) < 0 && indexOfEntity(uow.dirties, value) < 0 && indexOfEntity(uow.deletes, value) < 0 {
+			*entities = append(*entities, entity)
+		}
+	}
+	return nil
+}
+
+func (uow *UnitOfWork) entity(value interface{}) (unitOfWorkEntity, error) {
+	if rv := reflect.ValueOf(value); rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return unitOfWorkEntity{}, ErrInvalidValue
+	}
+
+	s, err := schema.Parse(value, uow.db.cacheStore, uow.db.NamingStrategy)
+	return unitOfWorkEntity{schema: s, value: value}, err
+}
+
+func indexOfEntity(entities []unitOfWorkEntity, value interface{}) int {
+	for idx, entity := range entities {
+		if entity.value == value {
+			return idx
+		}
+	}
+	return -1
+}
+
+// First finds the first record matching given conditions into dest, dest should be a pointer to a struct pointer,
+// it will be set to the loaded instance if the record was loaded before
+func (uow *UnitOfWork) First(dest interface{}, conds ...interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Ptr || destValue.Elem().Type().Elem().Kind() != reflect.Struct {
+		return ErrInvalidValue
+	}
+
+	value := reflect.New(destValue.Elem().Type().Elem())
+	if err := uow.db.First(value.Interface(), conds...).Error; err != nil {
+		return err
+	}
+
+	destValue.Elem().Set(reflect.ValueOf(uow.identify(value.Interface())))
+	return nil
+}
+
+// Find finds all records matching given conditions into dest, dest should be a pointer to a slice of struct pointers,
+// records loaded before are replaced with the loaded instances
+func (uow *UnitOfWork) Find(dest interface{}, conds ...interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Slice ||
+		destValue.Elem().Type().Elem().Kind() != reflect.Ptr || destValue.Elem().Type().Elem().Elem().Kind() != reflect.Struct {
+		return ErrInvalidValue
+	}
+
+	values := reflect.New(destValue.Elem().Type())
+	if err := uow.db.Find(values.Interface(), conds...).Error; err != nil {
+		return err
+	}
+
+	results := reflect.MakeSlice(destValue.Elem().Type(), 0, values.Elem().Len())
+	for i := 0; i < values.Elem().Len(); i++ {
+		results = reflect.Append(results, reflect.ValueOf(uow.identify(values.Elem().Index(i).Interface())))
+	}
+	destValue.Elem().Set(results)
+	return nil
+}
+
+// identify returns the loaded instance of value from identity map, value will be stored if not loaded before
+func (uow *UnitOfWork) identify(value interface{}) interface{} {
+	uow.mux.Lock()
+	defer uow.mux.Unlock()
+
+	s, key, ok := uow.identityKey(value)
+	if !ok {
+		return value
+	}
+
+	if loaded, ok := uow.identity[s][key]; ok {
+		return loaded
+	}
+
+	if uow.identity[s] == nil {
+		uow.identity[s] = map[string]interface{}{}
+	}
+	uow.identity[s][key] = value
+	return value
+}
+
+func (uow *UnitOfWork) identityKey(value interface{}) (*schema.Schema, string, bool) {
+	s, err := schema.Parse(value, uow.db.cacheStore, uow.db.NamingStrategy)
+	if err != nil || len(s.PrimaryFields) == 0 {
+		return nil, "", false
+	}
+
+	var (
+		rv     = reflect.Indirect(reflect.ValueOf(value))
+		values = make([]interface{}, 0, len(s.PrimaryFields))
+	)
+	for _, field := range s.PrimaryFields {
+		v, isZero := field.ValueOf(uow.db.Statement.Context, rv)
+		if isZero {
+			return nil, "", false
+		}
+		values = append(values, v)
+	}
+	return s, utils.ToStringKey(values...), true
+}
+
+// Commit creates, updates and deletes registered entities in dependency order within one transaction,
+// trackable entities in the identity map are updated if they are changed, entities registered by hooks during
+// committing are kept for the next commit
+func (uow *UnitOfWork) Commit() error {
+	uow.mux.Lock()
+	news, deletes := append([]unitOfWorkEntity{}, uow.news...), append([]unitOfWorkEntity{}, uow.deletes...)
+	dirties := append([]unitOfWorkEntity{}, uow.dirties...)
+	for s, values := range uow.identity {
+		for _, value := range values {
+			if indexOfEntity(dirties, value) < 0 && indexOfEntity(deletes, value) < 0 {
+				stmt := &Statement{DB: uow.db, Context: uow.db.Statement.Context, Schema: s, ReflectValue: reflect.Indirect(reflect.ValueOf(value))}
+				if len(stmt.Changes()) > 0 {
+					dirties = append(dirties, unitOfWorkEntity{schema: s, value: value})
+				}
+			}
+		}
+	}
+	// unlock before flushing, hooks might call back into the unit of work
+	uow.mux.Unlock()
+
+	schemas := uow.orderedSchemas(news, dirties, deletes)
+	err := uow.db.Transaction(func(tx *DB) error {
+		for _, s := range schemas {
+			if values := entitiesOf(news, s); values.Len() > 0 {
+				if err := tx.Create(values.Interface()).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, s := range schemas {
+			for _, entity := range dirties {
+				if entity.schema != s {
+					continue
+				}
+
+				stmt := &Statement{DB: tx, Context: tx.Statement.Context, Schema: s, ReflectValue: reflect.Indirect(reflect.ValueOf(entity.value))}
+				if stmt.Changes() != nil {
+					if err := tx.SaveChanges(entity.value).Error; err != nil {
+						return err
+					}
+				} else if err := tx.Save(entity.value).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		for i := len(schemas) - 1; i >= 0; i-- {
+			if values := entitiesOf(deletes, schemas[i]); values.Len() > 0 {
+				if err := tx.Delete(values.Interface()).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+
+	if err == nil {
+		uow.mux.Lock()
+		defer uow.mux.Unlock()
+
+		for _, entity := range deletes {
+			if s, key, ok := uow.identityKey(entity.value); ok {
+				delete(uow.identity[s], key)
+			}
+		}
+
+		for _, entity := range append(news, dirties...) {
+			stmt := &Statement{DB: uow.db, Context: uow.db.Statement.Context, Schema: entity.schema, ReflectValue: reflect.Indirect(reflect.ValueOf(entity.value))}
+			stmt.TakeSnapshot()
+		}
+
+		for _, entity := range news {
+			if s, key, ok := uow.identityKey(entity.value); ok {
+				if uow.identity[s] == nil {
+					uow.identity[s] = map[string]interface{}{}
+				}
+				uow.identity[s][key] = entity.value
+			}
+		}
+
+		uow.news = withoutEntities(uow.news, news)
+		uow.dirties = withoutEntities(uow.dirties, dirties)
+		uow.deletes = withoutEntities(uow.deletes, deletes)
+	}
+	return err
+}
+
+// withoutEntities returns entities not in committed
+func withoutEntities(entities, committed []unitOfWorkEntity) []unitOfWorkEntity {
+	var results []unitOfWorkEntity
+	for _, entity := range entities {
+		if indexOfEntity(committed, entity.value) < 0 {
+			results = append(results, entity)
+		}
+	}
+	return results
+}
+
+// Rollback discards registered entities and clears the identity map
+func (uow *UnitOfWork) Rollback() {
+	uow.mux.Lock()
+	defer uow.mux.Unlock()
+
+	uow.news, uow.dirties, uow.deletes = nil, nil, nil
+	uow.identity = map[*schema.Schema]map[string]interface{}{}
+}
+
+// orderedSchemas returns schemas of registered entities, ordered by their dependencies
+func (uow *UnitOfWork) orderedSchemas(news, dirties, deletes []unitOfWorkEntity) []*schema.Schema {
+	var (
+		schemas []*schema.Schema
+		models  []interface{}
+		parsed  = map[*schema.Schema]bool{}
+	)
+
+	for _, entities := range [][]unitOfWorkEntity{news, dirties, deletes} {
+		for _, entity := range entities {
+			if !parsed[entity.schema] {
+				parsed[entity.schema] = true
+				schemas = append(schemas, entity.schema)
+				models = append(models, entity.value)
+			}
+		}
+	}
+
+	if reorderer, ok := uow.db.Migrator().(interface {
+		ReorderModels(values []interface{}, autoAdd bool) []interface{}
+	}); ok && len(models) > 1 {
+		ordered := make([]*schema.Schema, 0, len(schemas))
+		for _, model := range reorderer.ReorderModels(models, true) {
+			for idx, value := range models {
+				if value == model {
+					ordered = append(ordered, schemas[idx])
+				}
+			}
+		}
+
+		if len(ordered) == len(schemas) {
+			return ordered
+		}
+	}
+	return schemas
+}
+
+// entitiesOf returns a slice of entities belong to schema s
+func entitiesOf(entities []unitOfWorkEntity, s *schema.Schema) reflect.Value {
+	var values reflect.Value
+	for _, entity := range entities {
+		if entity.schema == s {
+			if !values.IsValid() {
+				values = reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(entity.value)), 0, 1)
+			}
+			values = reflect.Append(values, reflect.ValueOf(entity.value))
+		}
+	}
+
+	if !values.IsValid() {
+		return reflect.ValueOf([]interface{}{})
+	}
+	return values
+}
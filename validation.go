@@ -0,0 +1,177 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm/schema"
+)
+
+// Validator validates the value of fields with tag `validate` before create and update, configure it with
+// `Config.Validator` to enable validation, e.g:
+//
+//	type User struct {
+//		ID    uint
+//		Name  string `validate:"required,max=64"`
+//		Email string `validate:"omitempty,email"`
+//		Role  string `validate:"oneof=admin|member"`
+//	}
+//
+//	db, err := gorm.Open(sqlite.Open("gorm.db"), &gorm.Config{Validator: gorm.DefaultValidator{}})
+//
+// only the fields written to database are validated, e.g: selected, non-zero fields when updating with struct, keys
+// of the map when updating with map
+type Validator interface {
+	Validate(ctx context.Context, field *schema.Field, value interface{}) error
+}
+
+// FieldError validation error of a field
+type FieldError struct {
+	Field  string // name of the struct field
+	DBName string
+	Err    error
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s (%s) %v", e.Field, e.DBName, e.Err)
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError validation errors of all invalid fields, returned by create and update
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for idx, err := range e.Errors {
+		messages[idx] = err.Error()
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Fields returns the names of invalid fields
+func (e *ValidationError) Fields() []string {
+	fields := make([]string, len(e.Errors))
+	for idx, err := range e.Errors {
+		fields[idx] = err.Field
+	}
+	return fields
+}
+
+// DefaultValidator validates fields with rules separated by comma, supported rules:
+//
+//	required       value should not be zero
+//	omitempty      skip other rules if value is zero
+//	min=N, max=N   minimum, maximum of numbers or length of string, slice, map
+//	len=N          length of string, slice, map
+//	email          valid email address
+//	oneof=a|b      one of the values
+type DefaultValidator struct{}
+
+// Validate implements Validator interface
+func (DefaultValidator) Validate(ctx context.Context, field *schema.Field, value interface{}) error {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	isZero := !rv.IsValid() || rv.IsZero() || (rv.Kind() == reflect.Ptr && rv.IsNil())
+
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		name, param := strings.TrimSpace(rule), ""
+		if idx := strings.Index(name, "="); idx >= 0 {
+			name, param = name[:idx], name[idx+1:]
+		}
+
+		switch name {
+		case "", "-":
+		case "required":
+			if isZero {
+				return errors.New("is required")
+			}
+		case "omitempty":
+			if isZero {
+				return nil
+			}
+		case "min", "max", "len":
+			if err := validateSize(rv, name, param); err != nil {
+				return err
+			}
+		case "email":
+			if rv.Kind() != reflect.String {
+				return fmt.Errorf("unsupported type %v for rule email", rv.Kind())
+			}
+			if address, err := mail.ParseAddress(rv.String()); err != nil || address.Address != rv.String() {
+				return errors.New("must be a valid email address")
+			}
+		case "oneof":
+			var str string
+			if rv.IsValid() {
+				str = fmt.Sprint(rv.Interface())
+			}
+
+			var matched bool
+			for _, v := range strings.Split(param, "|") {
+				if v == str {
+					matched = true
+					break
+				}
+			}
+
+			if !matched {
+				return fmt.Errorf("must be one of %s", param)
+			}
+		default:
+			return fmt.Errorf("unsupported validation rule %s", name)
+		}
+	}
+	return nil
+}
+
+func validateSize(rv reflect.Value, rule, param string) error {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return fmt.Errorf("invalid param %s for rule %s", param, rule)
+	}
+
+	var (
+		size float64
+		unit = "length"
+	)
+
+	switch rv.Kind() {
+	case reflect.String:
+		size = float64(utf8.RuneCountInString(rv.String()))
+	case reflect.Slice, reflect.Map, reflect.Array:
+		size = float64(rv.Len())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size, unit = float64(rv.Int()), "value"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size, unit = float64(rv.Uint()), "value"
+	case reflect.Float32, reflect.Float64:
+		size, unit = rv.Float(), "value"
+	case reflect.Invalid, reflect.Ptr:
+		return nil
+	default:
+		return fmt.Errorf("unsupported type %v for rule %s", rv.Kind(), rule)
+	}
+
+	switch {
+	case rule == "min" && size < limit:
+		return fmt.Errorf("%s must be at least %s", unit, param)
+	case rule == "max" && size > limit:
+		return fmt.Errorf("%s must be at most %s", unit, param)
+	case rule == "len" && size != limit:
+		return fmt.Errorf("%s must be %s", unit, param)
+	}
+	return nil
+}